@@ -0,0 +1,85 @@
+package migrator
+
+import "strings"
+
+// Names of options of `ParseArgs`.
+const (
+	ArgVersion = "version"
+	ArgTags    = "tags"
+	ArgDryRun  = "dry-run"
+)
+
+// argAliases declares alternative names of options.
+var argAliases = map[string]string{"target": ArgVersion}
+
+// boolArgs declares options which have no value.
+var boolArgs = map[string]bool{ArgDryRun: true}
+
+// ParseArgs parses command arguments. The first positional argument is the command and the second one is
+// the version. Options may be specified as `--key=value` or `--key value`: `--version` (or `--target`),
+// `--tags=a,b` and `--dry-run`. It returns `ErrorUnexpectedCommand` if some option is unknown
+// and `ErrorCommandRequired` if there is no command.
+func ParseArgs(args []string) (command string, opts map[string]string, err error) {
+	opts = map[string]string{}
+
+	var positional []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if !strings.HasPrefix(arg, "--") {
+			positional = append(positional, arg)
+			continue
+		}
+
+		key, value, hasValue := strings.TrimPrefix(arg, "--"), "", false
+
+		if eq := strings.IndexByte(key, '='); eq != -1 {
+			key, value, hasValue = key[:eq], key[eq+1:], true
+		}
+
+		if alias, ok := argAliases[key]; ok {
+			key = alias
+		}
+
+		switch {
+		case key != ArgVersion && key != ArgTags && !boolArgs[key]:
+			err = ErrorUnexpectedCommand
+			return
+		case boolArgs[key] && !hasValue:
+			value = "true"
+		case !hasValue:
+			if i+1 >= len(args) {
+				err = ErrorUnexpectedCommand
+				return
+			}
+
+			i++
+			value = args[i]
+		}
+
+		opts[key] = value
+	}
+
+	if len(positional) == 0 {
+		err = ErrorCommandRequired
+		return
+	}
+
+	command = positional[0]
+
+	if _, ok := opts[ArgVersion]; !ok && len(positional) > 1 {
+		opts[ArgVersion] = positional[1]
+	}
+
+	return
+}
+
+// versionArgs returns the version option as a list of arguments of `parseVersion`.
+func versionArgs(opts map[string]string) []string {
+	if version, ok := opts[ArgVersion]; ok {
+		return []string{version}
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		command string
+		opts    map[string]string
+		wantErr error
+	}{
+		{"command", []string{"up"}, "up", map[string]string{}, nil},
+		{"positional version", []string{"up", "3"}, "up", map[string]string{ArgVersion: "3"}, nil},
+		{"version with value", []string{"up", "--version=3"}, "up", map[string]string{ArgVersion: "3"}, nil},
+		{"version as next argument", []string{"--version", "3", "up"}, "up", map[string]string{ArgVersion: "3"}, nil},
+		{"target alias", []string{"down", "--target=2"}, "down", map[string]string{ArgVersion: "2"}, nil},
+		{"option overrides positional version", []string{"up", "4", "--version=3"}, "up", map[string]string{ArgVersion: "3"}, nil},
+		{"tags and dry run", []string{"up", "--tags=a,b", "--dry-run"}, "up",
+			map[string]string{ArgTags: "a,b", ArgDryRun: "true"}, nil},
+		{"dry run with value", []string{"up", "--dry-run=false"}, "up", map[string]string{ArgDryRun: "false"}, nil},
+		{"unknown option", []string{"up", "--force"}, "", nil, ErrorUnexpectedCommand},
+		{"missing value", []string{"up", "--version"}, "", nil, ErrorUnexpectedCommand},
+		{"no command", []string{"--dry-run"}, "", nil, ErrorCommandRequired},
+		{"no arguments", nil, "", nil, ErrorCommandRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command, opts, err := ParseArgs(tt.args)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseArgs() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if command != tt.command || !reflect.DeepEqual(opts, tt.opts) {
+				t.Errorf("ParseArgs() = %q, %v, want %q, %v", command, opts, tt.command, tt.opts)
+			}
+		})
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	m := NewMigrator(db, []migration.Migration{tableMigration(2), tableMigration(3)}, WithAutoInit(true))
+
+	if _, _, err := m.Run(ctx, "init", "--dry-run"); !errors.Is(err, ErrorUnexpectedCommand) {
+		t.Errorf("Run(init --dry-run) error = %v, want %v", err, ErrorUnexpectedCommand)
+	}
+
+	if _, _, err := m.Run(ctx, "up", "2"); err != nil {
+		t.Fatalf("Run(up 2) error = %v", err)
+	}
+
+	tests := []struct {
+		args       []string
+		oldVersion int64
+		newVersion int64
+	}{
+		{[]string{"up", "--dry-run"}, 2, 3},
+		{[]string{"down", "--dry-run"}, 2, 1},
+		{[]string{"reset", "--dry-run"}, 2, 1},
+		{[]string{"set_version", "3", "--dry-run"}, 2, 3},
+	}
+
+	for _, tt := range tests {
+		oldVersion, newVersion, err := m.Run(ctx, tt.args...)
+		if err != nil {
+			t.Fatalf("Run(%v) error = %v", tt.args, err)
+		}
+
+		if oldVersion != tt.oldVersion || newVersion != tt.newVersion {
+			t.Errorf("Run(%v) = %d, %d, want %d, %d", tt.args, oldVersion, newVersion, tt.oldVersion, tt.newVersion)
+		}
+
+		if _, version, _ := m.Version(ctx); version != 2 {
+			t.Errorf("Version() after Run(%v) = %d, want 2", tt.args, version)
+		}
+	}
+
+	if db.Migrator().HasTable("t3") || !db.Migrator().HasTable("t2") {
+		t.Error("dry runs changed the database")
+	}
+}
+
+func TestRunTags(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	tagged := tableMigration(2)
+	tagged.Tags = []string{"data"}
+
+	m := NewMigrator(db, []migration.Migration{tagged, tableMigration(3)}, WithAutoInit(true))
+
+	if _, newVersion, err := m.Run(ctx, "up", "--tags=data"); err != nil || newVersion != 2 {
+		t.Fatalf("Run(up --tags=data) = %d, %v, want 2, nil", newVersion, err)
+	}
+
+	if !db.Migrator().HasTable("t2") || db.Migrator().HasTable("t3") {
+		t.Error("migrations without the tag are applied")
+	}
+}
@@ -0,0 +1,13 @@
+module github.com/Devoter/gorm-migrator/cmd/gorm-migrator
+
+go 1.16
+
+require (
+	github.com/Devoter/gorm-migrator v0.0.0
+	gorm.io/driver/mysql v1.1.3
+	gorm.io/driver/postgres v1.1.2
+	gorm.io/driver/sqlite v1.1.6
+	gorm.io/gorm v1.21.16
+)
+
+replace github.com/Devoter/gorm-migrator => ../../
@@ -0,0 +1,151 @@
+// Command gorm-migrator applies SQL migrations from a directory.
+//
+// Usage:
+//
+//	gorm-migrator --driver=postgres --dsn="host=localhost user=app dbname=app" --dir=./migrations up
+//
+// Flags may be replaced with `MIGRATOR_DRIVER`, `MIGRATOR_DSN` and `MIGRATOR_DIR` environment variables.
+// Migration files must correspond to `<version>_<name>.(up|down).sql`.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	migrator "github.com/Devoter/gorm-migrator"
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// result declares a JSON result of the command.
+type result struct {
+	OldVersion int64  `json:"old_version"`
+	NewVersion int64  `json:"new_version"`
+	Error      string `json:"error,omitempty"`
+}
+
+// options declares options of the command resolved from flags and environment variables.
+type options struct {
+	driver string
+	dsn    string
+	dir    string
+	json   bool
+	args   []string
+}
+
+func main() {
+	opts, err := parseOptions(os.Args[0], os.Args[1:], os.Getenv)
+	if err == flag.ErrHelp {
+		return
+	} else if err != nil {
+		os.Exit(2)
+	}
+
+	oldVersion, newVersion, err := run(opts, os.Stdout)
+
+	if opts.json {
+		res := result{OldVersion: oldVersion, NewVersion: newVersion}
+
+		if err != nil {
+			res.Error = err.Error()
+		}
+
+		_ = json.NewEncoder(os.Stdout).Encode(res)
+	} else if err == nil {
+		fmt.Printf("%d -> %d\n", oldVersion, newVersion)
+	}
+
+	if err != nil {
+		if !opts.json {
+			fmt.Fprintln(os.Stderr, err)
+		}
+
+		os.Exit(1)
+	}
+}
+
+// parseOptions parses command line arguments. Flags take precedence over environment variables
+// which are read by `getenv`.
+func parseOptions(name string, args []string, getenv func(string) string) (opts options, err error) {
+	dir := getenv("MIGRATOR_DIR")
+	if dir == "" {
+		dir = "migrations"
+	}
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.StringVar(&opts.driver, "driver", getenv("MIGRATOR_DRIVER"), "database driver: postgres, mysql or sqlite")
+	flags.StringVar(&opts.dsn, "dsn", getenv("MIGRATOR_DSN"), "data source name")
+	flags.StringVar(&opts.dir, "dir", dir, "directory of migration files")
+	flags.BoolVar(&opts.json, "json", false, "write results as JSON")
+
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s [flags] command [version]\n", name)
+		flags.PrintDefaults()
+	}
+
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+
+	opts.args = flags.Args()
+
+	return
+}
+
+// run loads migrations from the directory and runs the command. The output of the migrator is written to `out`.
+func run(opts options, out io.Writer) (oldVersion int64, newVersion int64, err error) {
+	var dialector gorm.Dialector
+
+	if dialector, err = open(opts.driver, opts.dsn); err != nil {
+		return
+	}
+
+	var db *gorm.DB
+
+	if db, err = gorm.Open(dialector, &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)}); err != nil {
+		return
+	}
+
+	var migrations []migration.Migration
+
+	if migrations, err = migration.LoadFromFS(os.DirFS(opts.dir), "*.sql"); err != nil {
+		return
+	}
+
+	migratorOpts := []migrator.MigratorOption{}
+
+	if opts.json {
+		migratorOpts = append(migratorOpts, migrator.WithJSONOutput(out))
+	} else {
+		migratorOpts = append(migratorOpts, migrator.WithOutput(out))
+	}
+
+	return migrator.NewMigrator(db, migrations, migratorOpts...).Run(context.Background(), opts.args...)
+}
+
+// open returns a dialector of the driver.
+func open(driver, dsn string) (gorm.Dialector, error) {
+	if dsn == "" {
+		return nil, fmt.Errorf("DSN required")
+	}
+
+	switch driver {
+	case "postgres":
+		return postgres.Open(dsn), nil
+	case "mysql":
+		return mysql.Open(dsn), nil
+	case "sqlite":
+		return sqlite.Open(dsn), nil
+	default:
+		return nil, fmt.Errorf("unsupported driver %q", driver)
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseOptions(t *testing.T) {
+	env := map[string]string{
+		"MIGRATOR_DRIVER": "postgres",
+		"MIGRATOR_DSN":    "host=db",
+		"MIGRATOR_DIR":    "/migrations",
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want options
+	}{
+		{
+			name: "defaults",
+			args: []string{"up"},
+			want: options{dir: "migrations", args: []string{"up"}},
+		},
+		{
+			name: "environment",
+			args: []string{"down"},
+			env:  env,
+			want: options{driver: "postgres", dsn: "host=db", dir: "/migrations", args: []string{"down"}},
+		},
+		{
+			name: "flags override environment",
+			args: []string{"--driver=sqlite", "--dsn=test.db", "--dir=sql", "--json", "up", "3"},
+			env:  env,
+			want: options{driver: "sqlite", dsn: "test.db", dir: "sql", json: true, args: []string{"up", "3"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseOptions("gorm-migrator", tt.args, func(name string) string { return tt.env[name] })
+			if err != nil {
+				t.Fatalf("parseOptions() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseOptions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOptionsUnknownFlag(t *testing.T) {
+	if _, err := parseOptions("gorm-migrator", []string{"--unknown"}, func(string) string { return "" }); err == nil {
+		t.Error("parseOptions() error = nil, want an error")
+	}
+}
+
+func TestRun(t *testing.T) {
+	tmp := t.TempDir()
+	dir := filepath.Join(tmp, "migrations")
+	files := map[string]string{
+		"0002_create_users.up.sql":   "CREATE TABLE users (id integer)",
+		"0002_create_users.down.sql": "DROP TABLE users",
+		"0003_create_posts.up.sql":   "CREATE TABLE posts (id integer)",
+		"0003_create_posts.down.sql": "DROP TABLE posts",
+	}
+
+	if err := writeFiles(dir, files); err != nil {
+		t.Fatalf("write migrations: %v", err)
+	}
+
+	base := options{driver: "sqlite", dsn: filepath.Join(tmp, "test.db"), dir: dir}
+
+	tests := []struct {
+		args       []string
+		oldVersion int64
+		newVersion int64
+	}{
+		{[]string{"init"}, 0, 0},
+		{[]string{"up", "2"}, 1, 2},
+		{[]string{"up"}, 2, 3},
+		{[]string{"version"}, 3, 3},
+		{[]string{"down"}, 3, 2},
+		{[]string{"reset"}, 2, 1},
+	}
+
+	for _, tt := range tests {
+		opts := base
+		opts.args = tt.args
+
+		var out bytes.Buffer
+
+		oldVersion, newVersion, err := run(opts, &out)
+		if err != nil {
+			t.Fatalf("run(%v) error = %v", tt.args, err)
+		}
+
+		if oldVersion != tt.oldVersion || newVersion != tt.newVersion {
+			t.Errorf("run(%v) = %d, %d, want %d, %d", tt.args, oldVersion, newVersion, tt.oldVersion, tt.newVersion)
+		}
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name string
+		opts options
+	}{
+		{"no DSN", options{driver: "sqlite", dir: dir, args: []string{"up"}}},
+		{"unsupported driver", options{driver: "oracle", dsn: "db", dir: dir, args: []string{"up"}}},
+		{"unknown command", options{driver: "sqlite", dsn: filepath.Join(dir, "test.db"), dir: dir, args: []string{"unknown"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := run(tt.opts, ioutil.Discard); err == nil {
+				t.Error("run() error = nil, want an error")
+			}
+		})
+	}
+}
+
+// writeFiles creates the directory and writes files into it.
+func writeFiles(dir string, files map[string]string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package migrator
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// CompareResult declares a result of the comparison of histories of two migrators.
+// `Differences` contains sorted versions which are applied by only one of migrators.
+type CompareResult struct {
+	VersionMatch  bool
+	LocalVersion  int64
+	RemoteVersion int64
+	Differences   []int64
+}
+
+// CompareVersions returns `true` if current database revision versions of both migrators are equal.
+func (m *Migrator) CompareVersions(ctx context.Context, other *Migrator) (match bool, err error) {
+	var local, remote int64
+
+	if local, err = m.CurrentVersion(ctx); err != nil {
+		return
+	}
+
+	if remote, err = other.CurrentVersion(ctx); err != nil {
+		return
+	}
+
+	match = local == remote
+
+	return
+}
+
+// DetailedCompare compares current database revision versions and histories of both migrators. The version
+// and the history of every migrator are loaded while holding its lock.
+func (m *Migrator) DetailedCompare(ctx context.Context, other *Migrator) (res CompareResult, err error) {
+	var local, remote []migration.Migration
+
+	if res.LocalVersion, local, err = m.versionAndHistory(ctx); err != nil {
+		return
+	}
+
+	if res.RemoteVersion, remote, err = other.versionAndHistory(ctx); err != nil {
+		return
+	}
+
+	res.VersionMatch = res.LocalVersion == res.RemoteVersion
+	res.Differences = []int64{}
+	counts := make(map[int64]int, len(local)+len(remote))
+
+	for _, migr := range local {
+		counts[migr.Version]++
+	}
+
+	for _, migr := range remote {
+		counts[migr.Version]--
+	}
+
+	for version, count := range counts {
+		if count != 0 {
+			res.Differences = append(res.Differences, version)
+		}
+	}
+
+	sort.Slice(res.Differences, func(i, j int) bool {
+		return res.Differences[i] < res.Differences[j]
+	})
+
+	return
+}
+
+// versionAndHistory returns the current version and the history loaded under the same read lock.
+func (m *Migrator) versionAndHistory(ctx context.Context) (version int64, history []migration.Migration, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, version, err = m.version(ctx); err != nil {
+		return
+	}
+
+	history, err = m.loadHistory(m.session(ctx))
+
+	return
+}
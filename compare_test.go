@@ -0,0 +1,85 @@
+package migrator
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+func TestDetailedCompare(t *testing.T) {
+	ctx := context.Background()
+	local := NewMigrator(openTestDB(t), []migration.Migration{tableMigration(2), tableMigration(3)}, WithAutoInit(true))
+	remote := NewMigrator(openTestDB(t), []migration.Migration{tableMigration(2), tableMigration(4)}, WithAutoInit(true))
+
+	if _, _, err := local.Up(ctx, -1); err != nil {
+		t.Fatalf("local Up() error = %v", err)
+	}
+
+	if _, _, err := remote.Up(ctx, 2); err != nil {
+		t.Fatalf("remote Up() error = %v", err)
+	}
+
+	res, err := local.DetailedCompare(ctx, remote)
+	if err != nil {
+		t.Fatalf("DetailedCompare() error = %v", err)
+	}
+
+	want := CompareResult{VersionMatch: false, LocalVersion: 3, RemoteVersion: 2, Differences: []int64{3}}
+
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("DetailedCompare() = %+v, want %+v", res, want)
+	}
+}
+
+func TestDetailedCompareConcurrentUp(t *testing.T) {
+	const goroutines = 5
+
+	ctx := context.Background()
+	db := openTestDB(t)
+	migrations := []migration.Migration{tableMigration(2), tableMigration(3), tableMigration(4)}
+	m := NewMigrator(db, migrations, WithAutoInit(true))
+	other := NewMigrator(db, migrations, WithAutoInit(true))
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, 2*goroutines)
+
+	for i := 0; i < goroutines; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			if _, _, err := m.Up(ctx, -1); err != nil {
+				errs <- err
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			if _, err := other.DetailedCompare(ctx, m); err != nil {
+				errs <- err
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("concurrent call error = %v", err)
+	}
+
+	res, err := other.DetailedCompare(ctx, m)
+	if err != nil {
+		t.Fatalf("DetailedCompare() error = %v", err)
+	}
+
+	if !res.VersionMatch || len(res.Differences) != 0 {
+		t.Errorf("DetailedCompare() = %+v, want matching histories", res)
+	}
+}
@@ -0,0 +1,54 @@
+package migrator
+
+import (
+	"context"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// MigrationCount returns counts of registered, applied and pending migrations. Only applied migrations which are
+// registered are counted, so `pending` is the number of registered migrations which are not applied.
+// The initial zero-migration is counted as registered and applied. It is a lightweight alternative to `Status`.
+func (m *Migrator) MigrationCount(ctx context.Context) (total int, applied int, pending int, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if err = m.ensureInit(ctx); err != nil {
+		return
+	}
+
+	store := m.historyStore(m.session(ctx))
+	total = len(m.migrations)
+
+	if counter, ok := store.(versionCounter); ok {
+		versions := make([]int64, total)
+
+		for i, migr := range m.migrations {
+			versions[i] = migr.Version
+		}
+
+		var count int64
+
+		if count, err = counter.CountMigrationsOf(versions); err != nil {
+			return
+		}
+
+		applied = int(count)
+	} else {
+		history, listErr := store.ListMigrations()
+		if listErr != nil {
+			err = listErr
+			return
+		}
+
+		for _, migr := range history {
+			if migration.Migrations(m.migrations).Contains(migr.Version) {
+				applied++
+			}
+		}
+	}
+
+	pending = total - applied
+
+	return
+}
@@ -0,0 +1,86 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+func TestMigrationCount(t *testing.T) {
+	ctx := context.Background()
+	m := NewMigrator(openTestDB(t), []migration.Migration{tableMigration(2), tableMigration(3), tableMigration(4)})
+
+	steps := []struct {
+		name    string
+		run     func() error
+		applied int
+	}{
+		{"after Init", func() (err error) { _, _, err = m.Init(ctx); return }, 1},
+		{"after partial Up", func() (err error) { _, _, err = m.Up(ctx, 3); return }, 3},
+		{"after full Up", func() (err error) { _, _, err = m.Up(ctx, -1); return }, 4},
+	}
+
+	for _, step := range steps {
+		if err := step.run(); err != nil {
+			t.Fatalf("%s: error = %v", step.name, err)
+		}
+
+		total, applied, pending, err := m.MigrationCount(ctx)
+		if err != nil {
+			t.Fatalf("%s: MigrationCount() error = %v", step.name, err)
+		}
+
+		if total != 4 || applied != step.applied || pending != 4-step.applied {
+			t.Errorf("%s: MigrationCount() = %d, %d, %d, want 4, %d, %d",
+				step.name, total, applied, pending, step.applied, 4-step.applied)
+		}
+	}
+}
+
+func TestMigrationCountIgnoresUnregisteredVersions(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	applied := NewMigrator(db, []migration.Migration{tableMigration(2), tableMigration(3)}, WithAutoInit(true))
+
+	if _, _, err := applied.Up(ctx, -1); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	// the version 3 is applied but not registered, the version 4 is pending
+	m := NewMigrator(db, []migration.Migration{tableMigration(2), tableMigration(4)})
+
+	total, appliedCount, pending, err := m.MigrationCount(ctx)
+	if err != nil {
+		t.Fatalf("MigrationCount() error = %v", err)
+	}
+
+	if total != 3 || appliedCount != 2 || pending != 1 {
+		t.Errorf("MigrationCount() = %d, %d, %d, want 3, 2, 1", total, appliedCount, pending)
+	}
+}
+
+func TestMigrationCountWithFailedMigration(t *testing.T) {
+	ctx := context.Background()
+	failing := tableMigration(2)
+	failing.Up = func(db *gorm.DB) error { return errors.New("up failed") }
+
+	m := NewMigrator(openTestDB(t), []migration.Migration{failing, tableMigration(3)},
+		WithAutoInit(true), WithErrorStrategy(ContinueOnError))
+
+	// the table is initialized automatically
+	if total, applied, pending, err := m.MigrationCount(ctx); err != nil || total != 3 || applied != 1 || pending != 2 {
+		t.Errorf("MigrationCount() before Up() = %d, %d, %d, %v, want 3, 1, 2, nil", total, applied, pending, err)
+	}
+
+	if _, _, err := m.Up(ctx, -1); err == nil {
+		t.Fatal("Up() error = nil, want the up error")
+	}
+
+	if total, applied, pending, err := m.MigrationCount(ctx); err != nil || total != 3 || applied != 2 || pending != 1 {
+		t.Errorf("MigrationCount() = %d, %d, %d, %v, want 3, 2, 1, nil", total, applied, pending, err)
+	}
+}
@@ -0,0 +1,40 @@
+package migrator
+
+import "gorm.io/gorm"
+
+// sqliteDialect is the name of the GORM SQLite dialect.
+const sqliteDialect = "sqlite"
+
+// SQLiteDialectInit enables foreign keys and the WAL journal mode of a SQLite database. Note that the foreign keys
+// pragma affects only the connection which executes it, so the DSN option (e.g. `_foreign_keys=1`) is more reliable
+// for connection pools.
+func SQLiteDialectInit(db *gorm.DB) error {
+	if err := db.Exec("PRAGMA foreign_keys = ON").Error; err != nil {
+		return err
+	}
+
+	return db.Exec("PRAGMA journal_mode = WAL").Error
+}
+
+// dialectInit calls the dialect initialization function before the migrations table is created.
+// `SQLiteDialectInit` is used for SQLite databases outside of transactions if no function is configured.
+func (m *Migrator) dialectInit(db *gorm.DB) error {
+	if db == nil {
+		return nil
+	}
+
+	if m.config.DialectInit != nil {
+		return m.config.DialectInit(db)
+	}
+
+	// the journal mode cannot be changed inside a transaction
+	if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); inTx {
+		return nil
+	}
+
+	if db.Dialector != nil && db.Dialector.Name() == sqliteDialect {
+		return SQLiteDialectInit(db)
+	}
+
+	return nil
+}
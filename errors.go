@@ -1,5 +1,17 @@
 package migrator
 
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	// DirectionUp is the direction of applying migrations.
+	DirectionUp = "up"
+	// DirectionDown is the direction of rolling migrations back.
+	DirectionDown = "down"
+)
+
 // Error declares constant error type.
 type Error string
 
@@ -36,3 +48,207 @@ const ErrorTargetVersionNotFound = Error("Target migration version was not found
 
 // ErrorSomeMigrationsAreAbsent means that some migrations files are absent.
 const ErrorSomeMigrationsAreAbsent = Error("Some migrations are absent")
+
+// AbsentMigrationError declares an error of an applied migration which is absent in the migrations list.
+type AbsentMigrationError struct {
+	MissingVersion int64
+}
+
+func (e *AbsentMigrationError) Error() string {
+	return ErrorSomeMigrationsAreAbsent.Error() + ": version " + strconv.FormatInt(e.MissingVersion, 10)
+}
+
+// Is returns `true` if the target is `ErrorSomeMigrationsAreAbsent`.
+func (e *AbsentMigrationError) Is(target error) bool {
+	return target == ErrorSomeMigrationsAreAbsent
+}
+
+// ErrorInvalidStepCount means that the count of migration steps is not positive.
+const ErrorInvalidStepCount = Error("Invalid step count")
+
+// ErrorInvalidPage means that the page number or the page size of the history is not positive.
+const ErrorInvalidPage = Error("Invalid history page")
+
+// ErrorNothingToRedo means that the database is at the initial revision and there is no migration to redo.
+const ErrorNothingToRedo = Error("Nothing to redo")
+
+// ErrorInvalidVersionRange means that the lower bound of a version range is not less than the upper one.
+const ErrorInvalidVersionRange = Error("Invalid version range")
+
+// ErrorInvalidDirection means that the migration direction is neither `up` nor `down`.
+const ErrorInvalidDirection = Error("Invalid migration direction")
+
+// ErrorCannotSquashFuncMigrations means that a migration to squash is defined by functions instead of SQL statements.
+const ErrorCannotSquashFuncMigrations = Error("Cannot squash function-based migrations")
+
+// ErrorVersionValidationFailed means that a migration version does not satisfy the version validator.
+const ErrorVersionValidationFailed = Error("Version validation failed")
+
+// ErrorAlreadyInitialized means that the migrations table already contains the initial zero-migration.
+const ErrorAlreadyInitialized = Error("Migrations are already initialized")
+
+// ErrorAlreadyApplied means that the history already contains a record of the applied migration
+// with the same version.
+const ErrorAlreadyApplied = Error("Migration is already applied")
+
+// MigrationError declares an error of a migration function.
+type MigrationError struct {
+	Version   int64
+	Name      string
+	Direction string
+	Cause     error
+}
+
+func (e *MigrationError) Error() string {
+	return "Migration " + strconv.FormatInt(e.Version, 10) + " (" + e.Name + ") " + e.Direction + " failed: " + e.Cause.Error()
+}
+
+// Unwrap returns the cause of the error.
+func (e *MigrationError) Unwrap() error {
+	return e.Cause
+}
+
+// PostHookError declares an error of a `PostUp` or `PostDown` function of a migration.
+type PostHookError struct {
+	Version   int64
+	Name      string
+	Direction string
+	Cause     error
+}
+
+func (e *PostHookError) Error() string {
+	return "Post hook of migration " + strconv.FormatInt(e.Version, 10) + " (" + e.Name + ") " + e.Direction +
+		" failed: " + e.Cause.Error()
+}
+
+// Unwrap returns the cause of the error.
+func (e *PostHookError) Unwrap() error {
+	return e.Cause
+}
+
+// ErrLockNotAcquired means that the migrations lock was not acquired.
+const ErrLockNotAcquired = Error("Migrations lock was not acquired")
+
+// LockError declares an error of the migrations lock acquisition.
+type LockError struct {
+	Cause error
+}
+
+func (e *LockError) Error() string {
+	return ErrLockNotAcquired.Error() + ": " + e.Cause.Error()
+}
+
+// Unwrap returns the cause of the error.
+func (e *LockError) Unwrap() error {
+	return e.Cause
+}
+
+// Is returns `true` if the target is `ErrLockNotAcquired`.
+func (e *LockError) Is(target error) bool {
+	return target == ErrLockNotAcquired
+}
+
+// ErrorInvalidVersion means that some migration version is not positive.
+const ErrorInvalidVersion = Error("Invalid migration version")
+
+// ErrorNameTooLong means that some migration name is longer than the configured maximum length.
+const ErrorNameTooLong = Error("Migration name is too long")
+
+// ErrorNoopMigration means that some migration uses `DummyUpDown` as its `up` function.
+const ErrorNoopMigration = Error("Migration does nothing")
+
+// ErrorDuplicateVersion means that some migration version is registered more than once.
+const ErrorDuplicateVersion = Error("Duplicate migration version")
+
+// ErrorNilApplyFunc means that some migration has no `up` or `down` function.
+const ErrorNilApplyFunc = Error("Migration function is nil")
+
+// VersionError declares an error related to the specific migration version.
+type VersionError struct {
+	Version int64
+	Err     error
+}
+
+func (e *VersionError) Error() string {
+	return e.Err.Error() + ": version " + strconv.FormatInt(e.Version, 10)
+}
+
+// Unwrap returns the underlying error.
+func (e *VersionError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorChecksumMismatch means that the checksum of an applied migration differs from the actual one.
+const ErrorChecksumMismatch = Error("Migration checksum mismatch")
+
+// ChecksumMismatchError declares an error of the migration checksum validation.
+type ChecksumMismatchError struct {
+	Version  int64
+	Stored   string
+	Computed string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return ErrorChecksumMismatch.Error() + ": version " + strconv.FormatInt(e.Version, 10) + ", stored " + e.Stored +
+		", computed " + e.Computed
+}
+
+// Is returns `true` if the target is `ErrorChecksumMismatch`.
+func (e *ChecksumMismatchError) Is(target error) bool {
+	return target == ErrorChecksumMismatch
+}
+
+// ErrorUnsatisfiedDependency means that a migration dependency is not applied or is going to be rolled back
+// while the dependent migration stays applied.
+const ErrorUnsatisfiedDependency = Error("Migration dependency is not satisfied")
+
+// DependencyError declares an error of the migration dependency which can not be satisfied.
+type DependencyError struct {
+	Version   int64
+	DependsOn int64
+}
+
+func (e *DependencyError) Error() string {
+	return ErrorUnsatisfiedDependency.Error() + ": version " + strconv.FormatInt(e.Version, 10) + " depends on " +
+		strconv.FormatInt(e.DependsOn, 10)
+}
+
+// Is returns `true` if the target is `ErrorUnsatisfiedDependency`.
+func (e *DependencyError) Is(target error) bool {
+	return target == ErrorUnsatisfiedDependency
+}
+
+// RollbackError declares an error of rolling back the migrations applied before the failed one.
+type RollbackError struct {
+	Err         error
+	RollbackErr error
+}
+
+func (e *RollbackError) Error() string {
+	return e.Err.Error() + "; rollback failed: " + e.RollbackErr.Error()
+}
+
+// Unwrap returns the original error.
+func (e *RollbackError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError declares a list of errors of migrations which failed while the error strategy was `ContinueOnError`.
+type MultiError struct {
+	errors []error
+}
+
+func (e *MultiError) Error() string {
+	messages := make([]string, len(e.errors))
+
+	for i, err := range e.errors {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Errors returns a list of collected errors.
+func (e *MultiError) Errors() []error {
+	return e.errors
+}
@@ -0,0 +1,39 @@
+package migrator
+
+import (
+	"time"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// MigrationEvent declares an event of an applied or rolled back migration.
+type MigrationEvent struct {
+	Version   int64
+	Name      string
+	Direction string
+	Success   bool
+	Duration  time.Duration
+	Err       error
+}
+
+// sendEvent sends the event of the migration to the event channel without blocking. The event is dropped
+// if the channel is not ready to receive it.
+func (m *Migrator) sendEvent(migr *migration.Migration, direction string, elapsed time.Duration, err error) {
+	if m.config.EventChannel == nil {
+		return
+	}
+
+	evt := MigrationEvent{
+		Version:   migr.Version,
+		Name:      migr.Name,
+		Direction: direction,
+		Success:   err == nil,
+		Duration:  elapsed,
+		Err:       err,
+	}
+
+	select {
+	case m.config.EventChannel <- evt:
+	default:
+	}
+}
@@ -0,0 +1,14 @@
+package migrator
+
+import "github.com/Devoter/gorm-migrator/migration"
+
+// Find returns the registered migration with the specified version and `true` or a zero value and `false`
+// if there is no such migration.
+func (m *Migrator) Find(version int64) (migration.Migration, bool) {
+	return migration.Find(m.migrations, version)
+}
+
+// Contains returns `true` if a migration with the specified version is registered.
+func (m *Migrator) Contains(version int64) bool {
+	return migration.Migrations(m.migrations).Contains(version)
+}
@@ -2,4 +2,8 @@ module github.com/Devoter/gorm-migrator
 
 go 1.16
 
-require gorm.io/gorm v1.21.16
+require (
+	gorm.io/driver/postgres v1.1.2
+	gorm.io/driver/sqlite v1.1.6
+	gorm.io/gorm v1.21.16
+)
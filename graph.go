@@ -0,0 +1,76 @@
+package migrator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// MigrationGraph declares a graph of migration dependencies. It is intended for tooling and does not affect
+// applying of migrations.
+type MigrationGraph struct {
+	nodes []migration.Migration
+	edges [][2]int64
+	order []migration.Migration
+}
+
+// NewMigrationGraph returns a new graph of the migrations. Dependencies on versions which are absent in the list
+// are ignored. It returns `migration.CyclicDependencyError` if dependencies are circular.
+func NewMigrationGraph(migrations []migration.Migration) (*MigrationGraph, error) {
+	order, err := migration.SortByDependencies(migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]migration.Migration, len(migrations))
+	copy(nodes, migrations)
+	sort.Sort(migration.Migrations(nodes))
+
+	edges := [][2]int64{}
+
+	for _, mig := range nodes {
+		for _, dep := range mig.DependsOn {
+			if migration.Migrations(nodes).Contains(dep) {
+				edges = append(edges, [2]int64{dep, mig.Version})
+			}
+		}
+	}
+
+	return &MigrationGraph{nodes: nodes, edges: edges, order: order}, nil
+}
+
+// Nodes returns migrations sorted by version.
+func (g *MigrationGraph) Nodes() []migration.Migration {
+	return g.nodes
+}
+
+// Edges returns dependency pairs: the first version of every pair is the dependency of the second one.
+func (g *MigrationGraph) Edges() [][2]int64 {
+	return g.edges
+}
+
+// TopoOrder returns migrations in the order of applying: every migration follows its dependencies.
+func (g *MigrationGraph) TopoOrder() []migration.Migration {
+	return g.order
+}
+
+// ToDOT returns the Graphviz DOT representation of the graph.
+func (g *MigrationGraph) ToDOT() string {
+	var b strings.Builder
+
+	b.WriteString("digraph migrations {\n")
+
+	for _, mig := range g.nodes {
+		fmt.Fprintf(&b, "\t%d [label=%q];\n", mig.Version, fmt.Sprintf("%d %s", mig.Version, mig.Name))
+	}
+
+	for _, edge := range g.edges {
+		fmt.Fprintf(&b, "\t%d -> %d;\n", edge[0], edge[1])
+	}
+
+	b.WriteString("}\n")
+
+	return b.String()
+}
@@ -0,0 +1,111 @@
+package migrator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// dependent returns a migration of the version which depends on the specified versions.
+func dependent(version int64, dependsOn ...int64) migration.Migration {
+	return migration.Migration{Version: version, DependsOn: dependsOn}
+}
+
+func TestMigrationGraph(t *testing.T) {
+	tests := []struct {
+		name       string
+		migrations []migration.Migration
+		order      []int64
+		edges      [][2]int64
+		cycle      []int64
+	}{
+		{
+			name:       "independent migrations are ordered by version",
+			migrations: []migration.Migration{dependent(4), dependent(2), dependent(3)},
+			order:      []int64{2, 3, 4},
+			edges:      [][2]int64{},
+		},
+		{
+			name:       "ties are broken by version",
+			migrations: []migration.Migration{dependent(2, 5), dependent(3), dependent(4, 5), dependent(5)},
+			order:      []int64{3, 5, 2, 4},
+			edges:      [][2]int64{{5, 2}, {5, 4}},
+		},
+		{
+			name:       "chain of dependencies",
+			migrations: []migration.Migration{dependent(2, 3), dependent(3, 4), dependent(4)},
+			order:      []int64{4, 3, 2},
+			edges:      [][2]int64{{3, 2}, {4, 3}},
+		},
+		{
+			name:       "missing dependency is ignored",
+			migrations: []migration.Migration{dependent(2, 9), dependent(3, 2)},
+			order:      []int64{2, 3},
+			edges:      [][2]int64{{2, 3}},
+		},
+		{
+			name:       "cycle",
+			migrations: []migration.Migration{dependent(2, 4), dependent(3, 2), dependent(4, 3)},
+			cycle:      []int64{2, 4, 3, 2},
+		},
+		{
+			name:       "self dependency",
+			migrations: []migration.Migration{dependent(2), dependent(3, 3)},
+			cycle:      []int64{3, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewMigrationGraph(tt.migrations)
+
+			if tt.cycle != nil {
+				var cycleErr *migration.CyclicDependencyError
+
+				if !errors.As(err, &cycleErr) || !errors.Is(err, migration.ErrorCircularDependency) {
+					t.Fatalf("NewMigrationGraph() error = %v, want a cyclic dependency error", err)
+				}
+
+				if !reflect.DeepEqual(cycleErr.Cycle, tt.cycle) {
+					t.Errorf("cycle = %v, want %v", cycleErr.Cycle, tt.cycle)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("NewMigrationGraph() error = %v", err)
+			}
+
+			if got := versionsOf(g.TopoOrder()); !reflect.DeepEqual(got, tt.order) {
+				t.Errorf("TopoOrder() versions = %v, want %v", got, tt.order)
+			}
+
+			if got := g.Edges(); !reflect.DeepEqual(got, tt.edges) {
+				t.Errorf("Edges() = %v, want %v", got, tt.edges)
+			}
+		})
+	}
+}
+
+func TestMigrationGraphToDOT(t *testing.T) {
+	g, err := NewMigrationGraph([]migration.Migration{
+		{Version: 2, Name: "create_users"},
+		{Version: 3, Name: "create_posts", DependsOn: []int64{2}},
+	})
+	if err != nil {
+		t.Fatalf("NewMigrationGraph() error = %v", err)
+	}
+
+	want := "digraph migrations {\n" +
+		"\t2 [label=\"2 create_users\"];\n" +
+		"\t3 [label=\"3 create_posts\"];\n" +
+		"\t2 -> 3;\n" +
+		"}\n"
+
+	if got := g.ToDOT(); got != want {
+		t.Errorf("ToDOT() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,126 @@
+package migrator
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// History returns a sorted list of applied migrations. Definitions of registered migrations (name, functions,
+// SQL statements, etc.) are copied to the corresponding entries while history fields are kept.
+func (m *Migrator) History(ctx context.Context) (history []migration.Migration, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if history, err = m.loadHistory(m.session(ctx)); err != nil {
+		return
+	}
+
+	m.describeHistory(history)
+
+	return
+}
+
+// HistoryPage returns the page of the sorted list of applied migrations (see `History`) and the total count
+// of applied migrations. Pages are numbered from 1. It returns `ErrorInvalidPage` if the page number
+// or the page size is not positive.
+func (m *Migrator) HistoryPage(ctx context.Context, page, pageSize int) (history []migration.Migration, total int64, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if page < 1 || pageSize < 1 {
+		err = ErrorInvalidPage
+		return
+	}
+
+	store := m.historyStore(m.session(ctx))
+	offset := (page - 1) * pageSize
+
+	if pager, ok := store.(historyPager); ok {
+		if total, err = pager.CountMigrations(); err != nil {
+			return
+		}
+
+		if history, err = pager.ListMigrationsPage(offset, pageSize); err != nil {
+			return
+		}
+	} else {
+		var all []migration.Migration
+
+		if all, err = store.ListMigrations(); err != nil {
+			return
+		}
+
+		total = int64(len(all))
+		history = []migration.Migration{}
+
+		if offset < len(all) {
+			end := offset + pageSize
+
+			if end > len(all) {
+				end = len(all)
+			}
+
+			history = all[offset:end]
+		}
+	}
+
+	for i := range history {
+		history[i].Stored = true
+	}
+
+	m.describeHistory(history)
+
+	return
+}
+
+// describeHistory copies definitions of registered migrations to the corresponding entries of the sorted history.
+func (m *Migrator) describeHistory(history []migration.Migration) {
+	j := 0
+
+	for i := range history {
+		for j < len(m.migrations) && m.migrations[j].Less(&history[i]) {
+			j++
+		}
+
+		if j < len(m.migrations) && m.migrations[j].Eq(&history[i]) {
+			migr := m.migrations[j]
+			migr.Stored = true
+			migr.AppliedAt = history[i].AppliedAt
+			migr.ExecutionDurationMs = history[i].ExecutionDurationMs
+			migr.Checksum = history[i].Checksum
+			migr.AppliedDirection = history[i].AppliedDirection
+			migr.AppliedBy = history[i].AppliedBy
+			migr.AppliedHost = history[i].AppliedHost
+			history[i] = migr
+		}
+	}
+}
+
+// CompactHistory deletes history records of rolled back migrations, so the history contains only the current state.
+// Records are deleted inside a transaction. It does nothing if the store does not keep such records.
+func (m *Migrator) CompactHistory(ctx context.Context) error {
+	return m.withLock(ctx, func() error {
+		db := m.session(ctx)
+		compactor, ok := m.historyStore(db).(historyCompactor)
+
+		if !ok {
+			return nil
+		}
+
+		if db == nil {
+			return compactor.CompactHistory()
+		}
+
+		return db.Transaction(func(tx *gorm.DB) error {
+			return m.historyStore(tx).(historyCompactor).CompactHistory()
+		})
+	})
+}
+
+// Compact is a shorthand for `CompactHistory`.
+func (m *Migrator) Compact(ctx context.Context) error {
+	return m.CompactHistory(ctx)
+}
@@ -0,0 +1,136 @@
+package migrator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+func TestCompactRolledBackHistory(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	m := NewMigrator(db, []migration.Migration{tableMigration(2), tableMigration(3)}, WithAutoInit(true))
+
+	if _, _, err := m.Up(ctx, -1); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	if _, _, err := m.Reset(ctx); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+
+	var records []migration.Migration
+
+	if err := db.Table(DefaultMigrationTable).Order("id").Find(&records).Error; err != nil {
+		t.Fatalf("find records: %v", err)
+	}
+
+	// the history keeps records of both runs of rolled back migrations until it is compacted
+	type entry struct {
+		version   int64
+		direction string
+	}
+
+	got := make([]entry, len(records))
+
+	for i, record := range records {
+		got[i] = entry{record.Version, record.AppliedDirection}
+	}
+
+	want := []entry{{1, DirectionUp}, {2, DirectionUp}, {3, DirectionUp}, {3, DirectionDown}, {2, DirectionDown}}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("records before Compact() = %v, want %v", got, want)
+	}
+
+	if err := m.Compact(ctx); err != nil {
+		t.Fatalf("Compact() error = %v", err)
+	}
+
+	records = nil
+
+	if err := db.Table(DefaultMigrationTable).Order("id").Find(&records).Error; err != nil {
+		t.Fatalf("find records: %v", err)
+	}
+
+	if got := versionsOf(records); !reflect.DeepEqual(got, []int64{1}) {
+		t.Errorf("record versions = %v, want [1]", got)
+	}
+}
+
+func TestHistoryPage(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	migrations := []migration.Migration{tableMigration(2), tableMigration(3), tableMigration(4), tableMigration(5)}
+
+	tests := []struct {
+		name  string
+		store MigrationStore
+	}{
+		// GORMStore pages the history by queries
+		{"pager", NewGORMStore(db, DefaultMigrationTable)},
+		{"other store", uncheckedStore{NewGORMStore(db, DefaultMigrationTable)}},
+	}
+
+	m := NewMigrator(db, migrations, WithAutoInit(true))
+
+	if _, _, err := m.Up(ctx, -1); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	// the rolled back migration is kept by the history table but is not applied
+	if _, _, err := m.Down(ctx); err != nil {
+		t.Fatalf("Down() error = %v", err)
+	}
+
+	pages := []struct {
+		page     int
+		pageSize int
+		versions []int64
+	}{
+		{1, 2, []int64{1, 2}},
+		{2, 2, []int64{3, 4}},
+		{3, 2, []int64{}},
+		{1, 10, []int64{1, 2, 3, 4}},
+		{2, 3, []int64{4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMigratorWithStore(tt.store, migrations, WithMigrationDB(db))
+
+			for _, p := range pages {
+				history, total, err := m.HistoryPage(ctx, p.page, p.pageSize)
+				if err != nil {
+					t.Fatalf("HistoryPage(%d, %d) error = %v", p.page, p.pageSize, err)
+				}
+
+				if total != 4 {
+					t.Errorf("HistoryPage(%d, %d) total = %d, want 4", p.page, p.pageSize, total)
+				}
+
+				if got := versionsOf(history); !reflect.DeepEqual(got, p.versions) {
+					t.Errorf("HistoryPage(%d, %d) = %v, want %v", p.page, p.pageSize, got, p.versions)
+				}
+
+				for _, migr := range history {
+					if !migr.Stored || (migr.Version > 1 && (migr.Up == nil || migr.Name != migrations[migr.Version-2].Name)) {
+						t.Errorf("HistoryPage(%d, %d) entry %d is not described", p.page, p.pageSize, migr.Version)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestHistoryPageInvalid(t *testing.T) {
+	m := NewMigrator(openTestDB(t), nil, WithAutoInit(true))
+
+	for _, p := range [][2]int{{0, 10}, {-1, 10}, {1, 0}, {1, -5}} {
+		if _, _, err := m.HistoryPage(context.Background(), p[0], p[1]); err != ErrorInvalidPage {
+			t.Errorf("HistoryPage(%d, %d) error = %v, want %v", p[0], p[1], err, ErrorInvalidPage)
+		}
+	}
+}
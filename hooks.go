@@ -0,0 +1,37 @@
+package migrator
+
+import "github.com/Devoter/gorm-migrator/migration"
+
+// Hooks declares callbacks which are called before and after migrations. `BeforeAll` and `AfterAll` are called
+// once per operation (e.g. `Up` or `Reset`), `BeforeEach` and `AfterEach` are called for every applied or rolled
+// back migration. Panics of hooks are not recovered.
+type Hooks struct {
+	BeforeEach func(m migration.Migration)
+	AfterEach  func(m migration.Migration, err error)
+	BeforeAll  func(direction string)
+	AfterAll   func(direction string, err error)
+}
+
+func (h Hooks) beforeEach(m migration.Migration) {
+	if h.BeforeEach != nil {
+		h.BeforeEach(m)
+	}
+}
+
+func (h Hooks) afterEach(m migration.Migration, err error) {
+	if h.AfterEach != nil {
+		h.AfterEach(m, err)
+	}
+}
+
+func (h Hooks) beforeAll(direction string) {
+	if h.BeforeAll != nil {
+		h.BeforeAll(direction)
+	}
+}
+
+func (h Hooks) afterAll(direction string, err error) {
+	if h.AfterAll != nil {
+		h.AfterAll(direction, err)
+	}
+}
@@ -0,0 +1,122 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+func TestHooksOrder(t *testing.T) {
+	ctx := context.Background()
+
+	var calls []string
+
+	hooks := Hooks{
+		BeforeAll: func(direction string) {
+			calls = append(calls, "before all "+direction)
+		},
+		BeforeEach: func(m migration.Migration) {
+			calls = append(calls, fmt.Sprintf("before %d", m.Version))
+		},
+		AfterEach: func(m migration.Migration, err error) {
+			calls = append(calls, fmt.Sprintf("after %d %s %v", m.Version, m.Name, err))
+		},
+		AfterAll: func(direction string, err error) {
+			calls = append(calls, fmt.Sprintf("after all %s %v", direction, err))
+		},
+	}
+	m := NewMigrator(openTestDB(t), []migration.Migration{tableMigration(2), tableMigration(3)},
+		WithHooks(hooks), WithAutoInit(true))
+
+	if _, _, err := m.Up(ctx, -1); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	want := []string{
+		"before all up",
+		"before 2", "after 2 create_t2 <nil>",
+		"before 3", "after 3 create_t3 <nil>",
+		"after all up <nil>",
+	}
+
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Up() hook calls = %q, want %q", calls, want)
+	}
+
+	calls = nil
+
+	if _, _, err := m.Reset(ctx); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+
+	want = []string{
+		"before all down",
+		"before 3", "after 3 create_t3 <nil>",
+		"before 2", "after 2 create_t2 <nil>",
+		"before 1", "after 1 - <nil>",
+		"after all down <nil>",
+	}
+
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Reset() hook calls = %q, want %q", calls, want)
+	}
+}
+
+func TestHooksReceiveMigrationError(t *testing.T) {
+	errUp := errors.New("up failed")
+	failing := migration.Migration{
+		Version: 2,
+		Name:    "failing",
+		Up:      func(db *gorm.DB) error { return errUp },
+		Down:    migration.DummyUpDown,
+	}
+
+	var (
+		got    migration.Migration
+		gotErr error
+		allErr error
+	)
+
+	hooks := Hooks{
+		AfterEach: func(m migration.Migration, err error) { got, gotErr = m, err },
+		AfterAll:  func(direction string, err error) { allErr = err },
+	}
+	m := NewMigrator(openTestDB(t), []migration.Migration{failing}, WithHooks(hooks), WithAutoInit(true))
+
+	if _, _, err := m.Up(context.Background(), -1); !errors.Is(err, errUp) {
+		t.Fatalf("Up() error = %v, want %v", err, errUp)
+	}
+
+	if got.Version != failing.Version || got.Name != failing.Name {
+		t.Errorf("AfterEach() migration = %d %q, want %d %q", got.Version, got.Name, failing.Version, failing.Name)
+	}
+
+	if !errors.Is(gotErr, errUp) {
+		t.Errorf("AfterEach() error = %v, want %v", gotErr, errUp)
+	}
+
+	if !errors.Is(allErr, errUp) {
+		t.Errorf("AfterAll() error = %v, want %v", allErr, errUp)
+	}
+}
+
+func TestHooksPanicPropagates(t *testing.T) {
+	const message = "after each panic"
+
+	hooks := Hooks{AfterEach: func(m migration.Migration, err error) { panic(message) }}
+	m := NewMigrator(openTestDB(t), []migration.Migration{tableMigration(2)}, WithHooks(hooks), WithAutoInit(true))
+
+	defer func() {
+		if r := recover(); r != message {
+			t.Errorf("recovered %v, want %q", r, message)
+		}
+	}()
+
+	m.Up(context.Background(), -1)
+}
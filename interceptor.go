@@ -0,0 +1,29 @@
+package migrator
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// StepFunc declares a func type which applies a migration step using the specified context.
+type StepFunc func(ctx context.Context) error
+
+// Interceptor declares a func type which wraps every migration step, e.g. to trace or measure it.
+// An interceptor must call `next` to apply the migration and should return its error.
+type Interceptor func(ctx context.Context, db *gorm.DB, migr migration.Migration, direction string, next StepFunc) error
+
+// intercept calls the step function through configured interceptors. The first interceptor is the outermost one.
+func (m *Migrator) intercept(ctx context.Context, db *gorm.DB, migr migration.Migration, direction string, next StepFunc) error {
+	for i := len(m.config.Interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := m.config.Interceptors[i], next
+
+		next = func(ctx context.Context) error {
+			return interceptor(ctx, db, migr, direction, inner)
+		}
+	}
+
+	return next(ctx)
+}
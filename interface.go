@@ -0,0 +1,16 @@
+package migrator
+
+import "context"
+
+// MigratorInterface declares an interface of a migrations manager.
+type MigratorInterface interface {
+	Init(ctx context.Context) (int64, int64, error)
+	Up(ctx context.Context, target int64) (int64, int64, error)
+	Down(ctx context.Context) (int64, int64, error)
+	Reset(ctx context.Context) (int64, int64, error)
+	Version(ctx context.Context) (int64, int64, error)
+	SetVersion(ctx context.Context, target int64) (int64, int64, error)
+	Run(ctx context.Context, args ...string) (int64, int64, error)
+}
+
+var _ MigratorInterface = (*Migrator)(nil)
@@ -0,0 +1,10 @@
+package migrator
+
+import "gorm.io/gorm"
+
+// LockStrategy declares an interface of a lock which prevents concurrent migration runs.
+// Implementations are available in the `lock` package.
+type LockStrategy interface {
+	Lock(db *gorm.DB) error
+	Unlock(db *gorm.DB) error
+}
@@ -0,0 +1,14 @@
+package lock
+
+// Error declares constant error type.
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+// ErrorLockTimeout means that the lock was not acquired during the specified timeout.
+const ErrorLockTimeout = Error("Lock timeout")
+
+// ErrorNotLocked means that `Unlock` was called without successful `Lock` call.
+const ErrorNotLocked = Error("Not locked")
@@ -0,0 +1,75 @@
+package lock
+
+import (
+	"database/sql"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// NoopLock is a lock strategy which does not lock anything.
+type NoopLock struct{}
+
+// Lock does nothing.
+func (NoopLock) Lock(db *gorm.DB) error {
+	return nil
+}
+
+// Unlock does nothing.
+func (NoopLock) Unlock(db *gorm.DB) error {
+	return nil
+}
+
+// sessionLock holds a dedicated connection while a session-level database lock is acquired.
+// It also serializes lock owners within the current process.
+type sessionLock struct {
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+// lock reserves a connection and calls `acquire` with it.
+func (s *sessionLock) lock(db *gorm.DB, acquire func(conn *sql.Conn) error) error {
+	s.mu.Lock()
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+
+	conn, err := sqlDB.Conn(db.Statement.Context)
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+
+	if err = acquire(conn); err != nil {
+		conn.Close()
+		s.mu.Unlock()
+		return err
+	}
+
+	s.conn = conn
+
+	return nil
+}
+
+// unlock calls `release` with the reserved connection and returns the connection to the pool.
+func (s *sessionLock) unlock(release func(conn *sql.Conn) error) error {
+	if s.conn == nil {
+		return ErrorNotLocked
+	}
+
+	defer s.mu.Unlock()
+
+	conn := s.conn
+	s.conn = nil
+
+	err := release(conn)
+
+	if closeErr := conn.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
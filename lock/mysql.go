@@ -0,0 +1,55 @@
+package lock
+
+import (
+	"database/sql"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MySQLNamedLock is a lock strategy based on MySQL named locks (`GET_LOCK`).
+// A negative timeout means infinite waiting.
+type MySQLNamedLock struct {
+	Name    string
+	Timeout time.Duration
+	session sessionLock
+}
+
+// NewMySQLNamedLock returns a new instance of MySQLNamedLock.
+func NewMySQLNamedLock(name string, timeout time.Duration) *MySQLNamedLock {
+	return &MySQLNamedLock{Name: name, Timeout: timeout}
+}
+
+// Lock acquires the named lock.
+func (l *MySQLNamedLock) Lock(db *gorm.DB) error {
+	ctx := db.Statement.Context
+	timeout := -1
+
+	if l.Timeout >= 0 {
+		timeout = int(l.Timeout / time.Second)
+	}
+
+	return l.session.lock(db, func(conn *sql.Conn) error {
+		var result sql.NullInt64
+
+		if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", l.Name, timeout).Scan(&result); err != nil {
+			return err
+		}
+
+		if !result.Valid || result.Int64 != 1 {
+			return ErrorLockTimeout
+		}
+
+		return nil
+	})
+}
+
+// Unlock releases the named lock.
+func (l *MySQLNamedLock) Unlock(db *gorm.DB) error {
+	ctx := db.Statement.Context
+
+	return l.session.unlock(func(conn *sql.Conn) error {
+		_, err := conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", l.Name)
+		return err
+	})
+}
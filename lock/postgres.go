@@ -0,0 +1,39 @@
+package lock
+
+import (
+	"database/sql"
+
+	"gorm.io/gorm"
+)
+
+// PostgreSQLAdvisoryLock is a lock strategy based on PostgreSQL session-level advisory locks.
+// `Lock` blocks until the lock is acquired or the context of the DB instance is done.
+type PostgreSQLAdvisoryLock struct {
+	Key     int64
+	session sessionLock
+}
+
+// NewPostgreSQLAdvisoryLock returns a new instance of PostgreSQLAdvisoryLock.
+func NewPostgreSQLAdvisoryLock(key int64) *PostgreSQLAdvisoryLock {
+	return &PostgreSQLAdvisoryLock{Key: key}
+}
+
+// Lock acquires the advisory lock.
+func (l *PostgreSQLAdvisoryLock) Lock(db *gorm.DB) error {
+	ctx := db.Statement.Context
+
+	return l.session.lock(db, func(conn *sql.Conn) error {
+		_, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", l.Key)
+		return err
+	})
+}
+
+// Unlock releases the advisory lock.
+func (l *PostgreSQLAdvisoryLock) Unlock(db *gorm.DB) error {
+	ctx := db.Statement.Context
+
+	return l.session.unlock(func(conn *sql.Conn) error {
+		_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.Key)
+		return err
+	})
+}
@@ -0,0 +1,61 @@
+package migrator
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Logger declares migrator logger interface. Fields are key-value pairs.
+type Logger interface {
+	Info(msg string, fields ...interface{})
+	Error(msg string, err error, fields ...interface{})
+}
+
+// NoopLogger is a logger which discards all messages.
+type NoopLogger struct{}
+
+// Info does nothing.
+func (NoopLogger) Info(msg string, fields ...interface{}) {}
+
+// Error does nothing.
+func (NoopLogger) Error(msg string, err error, fields ...interface{}) {}
+
+// StdLogger is a logger adapter which writes messages to a standard library logger.
+type StdLogger struct {
+	Logger *log.Logger
+}
+
+// NewStdLogger returns a new instance of StdLogger. If the logger is nil the standard logger is used.
+func NewStdLogger(l *log.Logger) *StdLogger {
+	if l == nil {
+		l = log.Default()
+	}
+
+	return &StdLogger{Logger: l}
+}
+
+// Info writes an informational message.
+func (l *StdLogger) Info(msg string, fields ...interface{}) {
+	l.Logger.Print("INFO " + msg + formatFields(fields))
+}
+
+// Error writes an error message.
+func (l *StdLogger) Error(msg string, err error, fields ...interface{}) {
+	l.Logger.Print("ERROR " + msg + formatFields(append([]interface{}{"error", err}, fields...)))
+}
+
+// formatFields returns key-value pairs formatted as ` key=value`.
+func formatFields(fields []interface{}) string {
+	var b strings.Builder
+
+	for i := 0; i < len(fields); i += 2 {
+		if i+1 < len(fields) {
+			fmt.Fprintf(&b, " %v=%v", fields[i], fields[i+1])
+		} else {
+			fmt.Fprintf(&b, " %v", fields[i])
+		}
+	}
+
+	return b.String()
+}
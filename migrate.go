@@ -0,0 +1,54 @@
+package migrator
+
+import (
+	"context"
+	"sync"
+
+	"gorm.io/gorm"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// Migrate initializes the migrations table if necessary and applies all pending migrations.
+func Migrate(db *gorm.DB, migrations []migration.Migration, opts ...MigratorOption) error {
+	return MigrateContext(context.Background(), db, migrations, opts...)
+}
+
+// MigrateContext is like `Migrate` but uses the specified context.
+func MigrateContext(ctx context.Context, db *gorm.DB, migrations []migration.Migration, opts ...MigratorOption) error {
+	m := NewMigrator(db, migrations, opts...)
+
+	if err := m.init(ctx); err != nil {
+		return err
+	}
+
+	_, _, err := m.Up(ctx, -1)
+
+	return err
+}
+
+// MigrateOnce returns a function which calls `Migrate` on the first call and returns its cached error
+// on subsequent calls. The function is safe for concurrent use.
+func MigrateOnce(db *gorm.DB, migrations []migration.Migration, opts ...MigratorOption) func() error {
+	var once sync.Once
+	var err error
+
+	return func() error {
+		once.Do(func() {
+			err = Migrate(db, migrations, opts...)
+		})
+
+		return err
+	}
+}
+
+// MustMigrateOnce is like `MigrateOnce` but the returned function panics on error.
+func MustMigrateOnce(db *gorm.DB, migrations []migration.Migration, opts ...MigratorOption) func() {
+	migrate := MigrateOnce(db, migrations, opts...)
+
+	return func() {
+		if err := migrate(); err != nil {
+			panic(err)
+		}
+	}
+}
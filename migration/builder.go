@@ -0,0 +1,66 @@
+package migration
+
+// Builder declares a builder of migrations.
+type Builder struct {
+	migration Migration
+}
+
+// NewBuilder returns a new migration builder.
+func NewBuilder(version int64, name string) *Builder {
+	return &Builder{migration: Migration{Version: version, Name: name}}
+}
+
+// WithUp sets the `up` function.
+func (b *Builder) WithUp(fn ApplyFunc) *Builder {
+	b.migration.Up = fn
+	return b
+}
+
+// WithDown sets the `down` function.
+func (b *Builder) WithDown(fn ApplyFunc) *Builder {
+	b.migration.Down = fn
+	return b
+}
+
+// WithUpCtx sets the `up` function which receives the context.
+func (b *Builder) WithUpCtx(fn ApplyFuncWithContext) *Builder {
+	b.migration.UpCtx = fn
+	return b
+}
+
+// WithDownCtx sets the `down` function which receives the context.
+func (b *Builder) WithDownCtx(fn ApplyFuncWithContext) *Builder {
+	b.migration.DownCtx = fn
+	return b
+}
+
+// WithTags appends tags.
+func (b *Builder) WithTags(tags ...string) *Builder {
+	b.migration.Tags = append(b.migration.Tags, tags...)
+	return b
+}
+
+// WithUpSQL sets the `up` SQL statement.
+func (b *Builder) WithUpSQL(sql string) *Builder {
+	b.migration.UpSQL = sql
+	return b
+}
+
+// WithDownSQL sets the `down` SQL statement.
+func (b *Builder) WithDownSQL(sql string) *Builder {
+	b.migration.DownSQL = sql
+	return b
+}
+
+// Build returns the migration. It returns `ErrorNilApplyFunc` if neither `up` function (with or without
+// the context) nor SQL statement is set.
+func (b *Builder) Build() (Migration, error) {
+	if !b.migration.HasUp() {
+		return Migration{}, ErrorNilApplyFunc
+	}
+
+	migr := b.migration
+	migr.Tags = append([]string(nil), b.migration.Tags...)
+
+	return migr, nil
+}
@@ -0,0 +1,55 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestBuilderBuild(t *testing.T) {
+	fn := func(db *gorm.DB) error { return nil }
+	ctxFn := func(ctx context.Context, db *gorm.DB) error { return nil }
+
+	tests := []struct {
+		name    string
+		builder *Builder
+		wantErr error
+	}{
+		{"function", NewBuilder(2, "fn").WithUp(fn).WithDown(fn), nil},
+		{"context function", NewBuilder(2, "ctx").WithUpCtx(ctxFn).WithDownCtx(ctxFn), nil},
+		{"SQL", NewBuilder(2, "sql").WithUpSQL("SELECT 1").WithDownSQL("SELECT 2"), nil},
+		{"down only", NewBuilder(2, "down").WithDown(fn), ErrorNilApplyFunc},
+		{"empty", NewBuilder(2, "empty"), ErrorNilApplyFunc},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migr, err := tt.builder.Build()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Build() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err == nil && (migr.Version != 2 || !migr.HasUp() || !migr.HasDown()) {
+				t.Errorf("Build() = %+v, want a reversible migration of the version 2", migr)
+			}
+		})
+	}
+}
+
+func TestBuilderCopiesTags(t *testing.T) {
+	builder := NewBuilder(2, "tagged").WithUpSQL("SELECT 1").WithTags("a")
+
+	first, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	builder.WithTags("b")
+
+	if !reflect.DeepEqual(first.Tags, []string{"a"}) {
+		t.Errorf("tags of the first migration = %v, want [a]", first.Tags)
+	}
+}
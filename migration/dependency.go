@@ -0,0 +1,140 @@
+package migration
+
+import "sort"
+
+// DetectCycles returns `CyclicDependencyError` if dependencies of the migrations are circular.
+// Dependencies on versions which are absent in the list are ignored.
+func DetectCycles(migrations []Migration) error {
+	deps := make(map[int64][]int64, len(migrations))
+	versions := make([]int64, 0, len(migrations))
+
+	for _, mig := range migrations {
+		if _, ok := deps[mig.Version]; !ok {
+			versions = append(versions, mig.Version)
+		}
+
+		deps[mig.Version] = append(deps[mig.Version], mig.DependsOn...)
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i] < versions[j]
+	})
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[int64]int, len(versions))
+	path := []int64{}
+
+	var visit func(version int64) []int64
+
+	visit = func(version int64) []int64 {
+		state[version] = visiting
+		path = append(path, version)
+
+		for _, dep := range deps[version] {
+			if _, ok := deps[dep]; !ok {
+				continue
+			}
+
+			switch state[dep] {
+			case visiting:
+				// the cycle is the part of the path from the dependency
+				for i, v := range path {
+					if v == dep {
+						return append(append([]int64{}, path[i:]...), dep)
+					}
+				}
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[version] = visited
+
+		return nil
+	}
+
+	for _, version := range versions {
+		if state[version] == unvisited {
+			if cycle := visit(version); cycle != nil {
+				return &CyclicDependencyError{Cycle: cycle}
+			}
+		}
+	}
+
+	return nil
+}
+
+// SortByDependencies returns a new slice of the migrations ordered so that every migration follows its
+// dependencies. Independent migrations are ordered by version. Dependencies on versions which are absent
+// in the list are ignored. It returns `CyclicDependencyError` (which is `ErrorCircularDependency`)
+// if the dependencies are circular.
+func SortByDependencies(migrations []Migration) ([]Migration, error) {
+	index := make(map[int64]int, len(migrations))
+
+	for i, mig := range migrations {
+		index[mig.Version] = i
+	}
+
+	// the number of unsorted dependencies and the list of dependent migrations of every migration
+	blockers := make([]int, len(migrations))
+	dependents := make([][]int, len(migrations))
+
+	for i, mig := range migrations {
+		for _, dep := range mig.DependsOn {
+			if j, ok := index[dep]; ok {
+				blockers[i]++
+				dependents[j] = append(dependents[j], i)
+			}
+		}
+	}
+
+	// indexes of migrations without unsorted dependencies in the descending order of versions
+	ready := []int{}
+	push := func(i int) {
+		pos := sort.Search(len(ready), func(k int) bool {
+			return migrations[ready[k]].Version < migrations[i].Version
+		})
+
+		ready = append(ready, 0)
+		copy(ready[pos+1:], ready[pos:])
+		ready[pos] = i
+	}
+
+	for i := range migrations {
+		if blockers[i] == 0 {
+			push(i)
+		}
+	}
+
+	sorted := make([]Migration, 0, len(migrations))
+
+	for len(ready) > 0 {
+		i := ready[len(ready)-1]
+		ready = ready[:len(ready)-1]
+		sorted = append(sorted, migrations[i])
+
+		for _, j := range dependents[i] {
+			if blockers[j]--; blockers[j] == 0 {
+				push(j)
+			}
+		}
+	}
+
+	if len(sorted) < len(migrations) {
+		if err := DetectCycles(migrations); err != nil {
+			return nil, err
+		}
+
+		return nil, ErrorCircularDependency
+	}
+
+	return sorted, nil
+}
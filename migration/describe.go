@@ -0,0 +1,50 @@
+package migration
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Describe returns a multi-line description of the migration: version, name, tags, kinds of `up` and `down`
+// implementations, reversibility and the checksum. Every line is formatted as `<Field>: <value>`.
+func Describe(m Migration) string {
+	var b strings.Builder
+
+	tags := "-"
+
+	if len(m.Tags) > 0 {
+		tags = strings.Join(m.Tags, ", ")
+	}
+
+	reversible := "no"
+
+	if m.HasDown() && !IsDummyUpDown(m.Down) {
+		reversible = "yes"
+	}
+
+	fmt.Fprintf(&b, "Version: %d\n", m.Version)
+	fmt.Fprintf(&b, "Name: %s\n", m.Name)
+	fmt.Fprintf(&b, "Tags: %s\n", tags)
+	fmt.Fprintf(&b, "Up: %s\n", describeApply(m.Up, m.UpCtx != nil, m.UpSQL))
+	fmt.Fprintf(&b, "Down: %s\n", describeApply(m.Down, m.DownCtx != nil, m.DownSQL))
+	fmt.Fprintf(&b, "Reversible: %s\n", reversible)
+	fmt.Fprintf(&b, "Checksum: %s\n", m.ComputeChecksum())
+
+	return b.String()
+}
+
+// describeApply returns a kind of the migration implementation in the order of precedence of `ApplyUp`.
+func describeApply(fn ApplyFunc, hasCtx bool, sql string) string {
+	switch {
+	case hasCtx:
+		return "context function"
+	case IsDummyUpDown(fn):
+		return "no-op function"
+	case fn != nil:
+		return "function"
+	case sql != "":
+		return "SQL"
+	default:
+		return "none"
+	}
+}
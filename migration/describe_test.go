@@ -0,0 +1,51 @@
+package migration
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestDescribe(t *testing.T) {
+	fn := func(db *gorm.DB) error { return nil }
+	ctxFn := func(ctx context.Context, db *gorm.DB) error { return nil }
+
+	tests := []struct {
+		name string
+		migr Migration
+	}{
+		{"sql", NewSQLMigration(2, "create_users", "CREATE TABLE users (id integer)", "DROP TABLE users")},
+		{"functions", Migration{Version: 3, Name: "seed_users", Tags: []string{"data", "users"}, Up: fn, Down: fn}},
+		{"context", Migration{Version: 4, Name: "backfill", UpCtx: ctxFn, Down: fn}},
+		{"irreversible", Migration{Version: 5, Name: "drop_legacy", UpSQL: "DROP TABLE legacy"}},
+		{"noop", NewNoopMigration(6)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Describe(tt.migr)
+			golden := filepath.Join("testdata", "describe_"+tt.name+".golden")
+
+			if *update {
+				if err := ioutil.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatalf("write golden file: %v", err)
+				}
+			}
+
+			want, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("read golden file: %v", err)
+			}
+
+			if got != string(want) {
+				t.Errorf("Describe() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
@@ -1,8 +1,32 @@
 package migration
 
-import "gorm.io/gorm"
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+)
 
 // DummyUpDown is a dummy migration function.
 func DummyUpDown(db *gorm.DB) error {
 	return nil
 }
+
+// dummyUpDownPointer is the address of `DummyUpDown` used to detect no-op migrations.
+var dummyUpDownPointer = reflect.ValueOf(DummyUpDown).Pointer()
+
+// IsDummyUpDown returns `true` if the function is `DummyUpDown`.
+func IsDummyUpDown(fn ApplyFunc) bool {
+	return fn != nil && reflect.ValueOf(fn).Pointer() == dummyUpDownPointer
+}
+
+// DummySQLMigration returns a migration which does nothing in both directions like the initial zero-migration.
+// It can be used as a placeholder in lists of SQL migrations.
+func DummySQLMigration(version int64, name string) Migration {
+	return Migration{Version: version, Name: name, Up: DummyUpDown, Down: DummyUpDown}
+}
+
+// NewNoopMigration returns a migration named `noop` which does nothing in both directions. It can be used
+// to reserve a version, e.g. the one used by another branch.
+func NewNoopMigration(version int64) Migration {
+	return Migration{Version: version, Name: "noop", Up: DummyUpDown, Down: DummyUpDown}
+}
@@ -0,0 +1,55 @@
+package migration
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Error declares constant error type.
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+// ErrorInvalidFilename means that the migration filename does not correspond to `<version>_<name>.(up|down).sql`.
+const ErrorInvalidFilename = Error("Invalid migration filename")
+
+// ErrorDuplicateFile means that more than one file declares the same migration direction.
+const ErrorDuplicateFile = Error("Duplicate migration file")
+
+// ErrorNameMismatch means that `up` and `down` files of the same migration version have different names.
+const ErrorNameMismatch = Error("Migration name mismatch")
+
+// ErrorNilApplyFunc means that the migration has neither `up` function nor SQL statement.
+const ErrorNilApplyFunc = Error("Migration function is nil")
+
+// ErrorCircularDependency means that migrations cannot be ordered because their dependencies are circular.
+const ErrorCircularDependency = Error("Circular migration dependency")
+
+// CyclicDependencyError declares an error of circular migration dependencies. The cycle starts and ends
+// with the same version.
+type CyclicDependencyError struct {
+	Cycle []int64
+}
+
+func (e *CyclicDependencyError) Error() string {
+	versions := make([]string, len(e.Cycle))
+
+	for i, version := range e.Cycle {
+		versions[i] = strconv.FormatInt(version, 10)
+	}
+
+	return "Cyclic migration dependency: " + strings.Join(versions, " -> ")
+}
+
+// Is returns `true` if the target is `ErrorCircularDependency`.
+func (e *CyclicDependencyError) Is(target error) bool {
+	return target == ErrorCircularDependency
+}
+
+// ErrorInvalidVersion means that the migration version is not positive.
+const ErrorInvalidVersion = Error("Invalid migration version")
+
+// ErrorNoDB means that an SQL migration is applied without a DB instance.
+const ErrorNoDB = Error("SQL migration requires a DB instance")
@@ -0,0 +1,126 @@
+package migration
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ParseFilename returns the version, the name and the direction of the migration file which corresponds to
+// `<version>_<name>.(up|down).sql`, e.g. `0003_create_orders.up.sql`. The version must be positive and the name
+// must consist of latin letters, digits and underscores. Errors wrap `ErrorInvalidFilename`.
+func ParseFilename(filename string) (version int64, name string, direction string, err error) {
+	if version, name, direction, err = parseFilename(filename); err != nil {
+		return
+	}
+
+	if SanitizeName(name) != name {
+		return 0, "", "", fmt.Errorf("%w: %s: name must consist of latin letters, digits and underscores",
+			ErrorInvalidFilename, filename)
+	}
+
+	return
+}
+
+// ParseFilenameLoose is like `ParseFilename` but also accepts Flyway-style filenames (`V3__create_orders.sql`
+// for `up` and `U3__create_orders.sql` for `down`) and dash-separated ones (`3-create-orders-up.sql`).
+// Names are sanitized with `SanitizeName`.
+func ParseFilenameLoose(filename string) (version int64, name string, direction string, err error) {
+	if version, name, direction, err = parseFilename(filename); err == nil {
+		name = SanitizeName(name)
+		return
+	}
+
+	strictErr := err
+	version, name, direction, err = 0, "", "", nil
+	base := path.Base(filename)
+
+	if !strings.HasSuffix(base, ".sql") {
+		err = strictErr
+		return
+	}
+
+	base = strings.TrimSuffix(base, ".sql")
+
+	var versionPart, namePart string
+
+	if sep := strings.Index(base, "__"); sep > 1 && (base[0] == 'V' || base[0] == 'U') {
+		// Flyway style
+		direction = "up"
+
+		if base[0] == 'U' {
+			direction = "down"
+		}
+
+		versionPart, namePart = base[1:sep], base[sep+2:]
+	} else if dash := strings.LastIndexByte(base, '-'); dash != -1 {
+		// dash-separated style
+		direction = base[dash+1:]
+		base = base[:dash]
+
+		if sep := strings.IndexByte(base, '-'); sep != -1 {
+			versionPart, namePart = base[:sep], base[sep+1:]
+		}
+	}
+
+	if direction != "up" && direction != "down" || namePart == "" {
+		direction = ""
+		err = strictErr
+		return
+	}
+
+	if version, err = parseFileVersion(filename, versionPart); err != nil {
+		direction = ""
+		return
+	}
+
+	name = SanitizeName(namePart)
+
+	return
+}
+
+// parseFilename returns the version, the name and the direction of the migration file. The name is not sanitized.
+func parseFilename(filename string) (version int64, name string, direction string, err error) {
+	base := path.Base(filename)
+
+	if !strings.HasSuffix(base, ".sql") {
+		err = fmt.Errorf("%w: %s: extension must be .sql", ErrorInvalidFilename, filename)
+		return
+	}
+
+	base = strings.TrimSuffix(base, ".sql")
+
+	if dot := strings.LastIndexByte(base, '.'); dot != -1 {
+		direction = base[dot+1:]
+		base = base[:dot]
+	}
+
+	if direction != "up" && direction != "down" {
+		return 0, "", "", fmt.Errorf("%w: %s: direction must be up or down", ErrorInvalidFilename, filename)
+	}
+
+	parts := strings.SplitN(base, "_", 2)
+
+	if len(parts) != 2 || parts[1] == "" {
+		return 0, "", "", fmt.Errorf("%w: %s: name is required", ErrorInvalidFilename, filename)
+	}
+
+	if version, err = parseFileVersion(filename, parts[0]); err != nil {
+		return 0, "", "", err
+	}
+
+	name = parts[1]
+
+	return
+}
+
+// parseFileVersion parses the version part of the filename.
+func parseFileVersion(filename, s string) (int64, error) {
+	version, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("%w: %s: version must be a positive integer", ErrorInvalidFilename, filename)
+	}
+
+	return version, nil
+}
@@ -0,0 +1,101 @@
+package migration
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseFilename(t *testing.T) {
+	tests := []struct {
+		filename  string
+		version   int64
+		name      string
+		direction string
+		wantErr   bool
+	}{
+		{"0003_create_orders.up.sql", 3, "create_orders", "up", false},
+		{"migrations/0003_create_orders.down.sql", 3, "create_orders", "down", false},
+		{"20230102150405_add_index.up.sql", 20230102150405, "add_index", "up", false},
+		{"0003_create_orders.sql", 0, "", "", true},
+		{"0003_create_orders.up.txt", 0, "", "", true},
+		{"0003_create_orders.apply.sql", 0, "", "", true},
+		{"0003.up.sql", 0, "", "", true},
+		{"0003_.up.sql", 0, "", "", true},
+		{"0000_zero.up.sql", 0, "", "", true},
+		{"-1_negative.up.sql", 0, "", "", true},
+		{"v3_create_orders.up.sql", 0, "", "", true},
+		{"0003_create-orders.up.sql", 0, "", "", true},
+		{"V3__create_orders.sql", 0, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			version, name, direction, err := ParseFilename(tt.filename)
+			if tt.wantErr {
+				if !errors.Is(err, ErrorInvalidFilename) {
+					t.Fatalf("ParseFilename() error = %v, want %v", err, ErrorInvalidFilename)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseFilename() error = %v", err)
+			}
+
+			if version != tt.version || name != tt.name || direction != tt.direction {
+				t.Errorf("ParseFilename() = %d, %q, %q, want %d, %q, %q",
+					version, name, direction, tt.version, tt.name, tt.direction)
+			}
+		})
+	}
+}
+
+func TestParseFilenameLoose(t *testing.T) {
+	tests := []struct {
+		filename  string
+		version   int64
+		name      string
+		direction string
+		wantErr   bool
+	}{
+		{"0003_create_orders.up.sql", 3, "create_orders", "up", false},
+		{"0003_create-orders.down.sql", 3, "create_orders", "down", false},
+		{"V3__create_orders.sql", 3, "create_orders", "up", false},
+		{"migrations/U3__create_orders.sql", 3, "create_orders", "down", false},
+		{"3-create-orders-up.sql", 3, "create_orders", "up", false},
+		{"3-create-orders-down.sql", 3, "create_orders", "down", false},
+		{"V3__create_orders.txt", 0, "", "", true},
+		{"V0__zero.sql", 0, "", "", true},
+		{"Vx__create_orders.sql", 0, "", "", true},
+		{"3-create-orders-apply.sql", 0, "", "", true},
+		{"3-up.sql", 0, "", "", true},
+		{"create_orders.sql", 0, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			version, name, direction, err := ParseFilenameLoose(tt.filename)
+			if tt.wantErr {
+				if !errors.Is(err, ErrorInvalidFilename) {
+					t.Fatalf("ParseFilenameLoose() error = %v, want %v", err, ErrorInvalidFilename)
+				}
+
+				if version != 0 || name != "" || direction != "" {
+					t.Errorf("ParseFilenameLoose() = %d, %q, %q on error", version, name, direction)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseFilenameLoose() error = %v", err)
+			}
+
+			if version != tt.version || name != tt.name || direction != tt.direction {
+				t.Errorf("ParseFilenameLoose() = %d, %q, %q, want %d, %q, %q",
+					version, name, direction, tt.version, tt.name, tt.direction)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package migration
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// String returns a human-readable representation of the migration, e.g. `[v5] create_orders (applied)`.
+// The value receiver makes `fmt` verbs like `%v` and `%s` use it for both values and pointers.
+func (mig Migration) String() string {
+	return fmt.Sprintf("[v%d] %s (%s)", mig.Version, mig.Name, mig.state())
+}
+
+// GoString returns a Go-syntax representation of the migration data fields for `%#v`.
+func (mig Migration) GoString() string {
+	return fmt.Sprintf("migration.Migration{Version: %d, Name: %q, Stored: %t, Checksum: %q}", mig.Version, mig.Name,
+		mig.Stored, mig.Checksum)
+}
+
+// String returns the migrations formatted as a table with a line per migration.
+func (ms Migrations) String() string {
+	var b strings.Builder
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE")
+
+	for _, mig := range ms {
+		fmt.Fprintf(w, "%d\t%s\t%s\n", mig.Version, mig.Name, mig.state())
+	}
+
+	_ = w.Flush()
+
+	return b.String()
+}
+
+// state returns the state of the migration for the representation.
+func (mig *Migration) state() string {
+	if mig.Stored {
+		return "applied"
+	}
+
+	return "pending"
+}
@@ -0,0 +1,63 @@
+package migration
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+)
+
+// LoadFromFS reads SQL migrations from files matching the pattern (e.g. `migrations/*.sql`).
+// Filenames must correspond to `<version>_<name>.(up|down).sql`, e.g. `0002_create_users.up.sql`.
+// Names are sanitized with `SanitizeName`. The result is sorted by version.
+func LoadFromFS(fsys fs.FS, pattern string) ([]Migration, error) {
+	matches, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+
+	for _, match := range matches {
+		version, name, direction, err := parseFilename(match)
+		if err != nil {
+			return nil, err
+		}
+
+		name = SanitizeName(name)
+
+		content, err := fs.ReadFile(fsys, match)
+		if err != nil {
+			return nil, err
+		}
+
+		migr, ok := byVersion[version]
+		if !ok {
+			migr = &Migration{Version: version, Name: name}
+			byVersion[version] = migr
+		} else if migr.Name != name {
+			return nil, fmt.Errorf("%w: %s", ErrorNameMismatch, match)
+		}
+
+		sql := &migr.UpSQL
+
+		if direction == "down" {
+			sql = &migr.DownSQL
+		}
+
+		if *sql != "" {
+			return nil, fmt.Errorf("%w: %s", ErrorDuplicateFile, match)
+		}
+
+		*sql = string(content)
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+
+	for _, migr := range byVersion {
+		migrations = append(migrations, *migr)
+	}
+
+	sort.Sort(Migrations(migrations))
+
+	return migrations, nil
+}
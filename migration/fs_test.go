@@ -0,0 +1,110 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestLoadFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/0010_add-index.up.sql":      {Data: []byte("CREATE INDEX idx_users_name ON users (name)")},
+		"migrations/0002_create_users.up.sql":   {Data: []byte("CREATE TABLE users (name TEXT)")},
+		"migrations/0002_create_users.down.sql": {Data: []byte("DROP TABLE users")},
+		"migrations/0010_add-index.down.sql":    {Data: []byte("DROP INDEX idx_users_name")},
+		"migrations/README.md":                  {Data: []byte("not a migration")},
+	}
+
+	migrations, err := LoadFromFS(fsys, "migrations/*.sql")
+	if err != nil {
+		t.Fatalf("LoadFromFS() error = %v", err)
+	}
+
+	want := []Migration{
+		{Version: 2, Name: "create_users", UpSQL: "CREATE TABLE users (name TEXT)", DownSQL: "DROP TABLE users"},
+		{Version: 10, Name: "add_index", UpSQL: "CREATE INDEX idx_users_name ON users (name)",
+			DownSQL: "DROP INDEX idx_users_name"},
+	}
+
+	if !reflect.DeepEqual(migrations, want) {
+		t.Fatalf("LoadFromFS() = %+v, want %+v", migrations, want)
+	}
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	for i := range migrations {
+		if err = migrations[i].ApplyUp(ctx, db); err != nil {
+			t.Fatalf("ApplyUp(%d) error = %v", migrations[i].Version, err)
+		}
+	}
+
+	if !db.Migrator().HasIndex("users", "idx_users_name") {
+		t.Error("loaded migrations are not applied")
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		if err = migrations[i].ApplyDown(ctx, db); err != nil {
+			t.Fatalf("ApplyDown(%d) error = %v", migrations[i].Version, err)
+		}
+	}
+
+	if db.Migrator().HasTable("users") {
+		t.Error("loaded migrations are not rolled back")
+	}
+}
+
+func TestLoadFromFSErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		wantErr error
+	}{
+		{
+			name:    "invalid filename",
+			fsys:    fstest.MapFS{"0002_create_users.sql": {}},
+			wantErr: ErrorInvalidFilename,
+		},
+		{
+			name: "name mismatch",
+			fsys: fstest.MapFS{
+				"0002_create_users.up.sql":    {Data: []byte("CREATE TABLE users (name TEXT)")},
+				"0002_create_people.down.sql": {Data: []byte("DROP TABLE users")},
+			},
+			wantErr: ErrorNameMismatch,
+		},
+		{
+			name: "duplicate file",
+			fsys: fstest.MapFS{
+				"0002_create_users.up.sql":   {Data: []byte("CREATE TABLE users (name TEXT)")},
+				"0002_create-users.up.sql":   {Data: []byte("CREATE TABLE people (name TEXT)")},
+				"0002_create_users.down.sql": {Data: []byte("DROP TABLE users")},
+			},
+			wantErr: ErrorDuplicateFile,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadFromFS(tt.fsys, "*.sql"); !errors.Is(err, tt.wantErr) {
+				t.Errorf("LoadFromFS() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadFromFSBadPattern(t *testing.T) {
+	if _, err := LoadFromFS(fstest.MapFS{}, "["); err == nil {
+		t.Error("LoadFromFS() error = nil, want a pattern error")
+	}
+}
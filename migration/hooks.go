@@ -0,0 +1,69 @@
+package migration
+
+import (
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuditFunc declares a func type which is called by `BeforeCreate` before a history record is inserted.
+// An error aborts the insertion.
+type AuditFunc func(tx *gorm.DB, m *Migration) error
+
+var audit = struct {
+	sync.RWMutex
+	funcs   []AuditFunc
+	created chan<- Migration
+}{}
+
+// RegisterAuditFunc adds a function which is called before every history record is inserted.
+func RegisterAuditFunc(fn AuditFunc) {
+	audit.Lock()
+	defer audit.Unlock()
+
+	audit.funcs = append(audit.funcs, fn)
+}
+
+// NotifyCreated sets a channel which receives inserted history records. Records are dropped if the channel
+// is not ready, so the migrator is never blocked. A nil channel disables notifications.
+func NotifyCreated(ch chan<- Migration) {
+	audit.Lock()
+	defer audit.Unlock()
+
+	audit.created = ch
+}
+
+// BeforeCreate is a GORM hook which sets `AppliedAt` to the current UTC time if it is not set and calls
+// registered audit functions.
+func (mig *Migration) BeforeCreate(tx *gorm.DB) error {
+	if mig.AppliedAt.IsZero() {
+		mig.AppliedAt = time.Now().UTC()
+	}
+
+	audit.RLock()
+	defer audit.RUnlock()
+
+	for _, fn := range audit.funcs {
+		if err := fn(tx, mig); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AfterCreate is a GORM hook which sends the inserted history record to the channel set by `NotifyCreated`.
+func (mig *Migration) AfterCreate(tx *gorm.DB) error {
+	audit.RLock()
+	defer audit.RUnlock()
+
+	if audit.created != nil {
+		select {
+		case audit.created <- *mig:
+		default:
+		}
+	}
+
+	return nil
+}
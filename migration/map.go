@@ -0,0 +1,48 @@
+package migration
+
+import (
+	"fmt"
+	"sort"
+)
+
+// NamedApplyFuncs declares a named pair of migration functions of `FromNamedMap`. It is an alias,
+// so map literals may use the struct type itself.
+type NamedApplyFuncs = struct {
+	Name     string
+	Up, Down ApplyFunc
+}
+
+// FromMap returns migrations with empty names sorted by version. Map values are pairs of `up` and `down`
+// functions. It returns `ErrorInvalidVersion` if some version is not positive and `ErrorNilApplyFunc`
+// if some `up` function is nil.
+func FromMap(m map[int64][2]ApplyFunc) ([]Migration, error) {
+	named := make(map[int64]NamedApplyFuncs, len(m))
+
+	for version, funcs := range m {
+		named[version] = NamedApplyFuncs{Up: funcs[0], Down: funcs[1]}
+	}
+
+	return FromNamedMap(named)
+}
+
+// FromNamedMap returns migrations sorted by version. It returns `ErrorInvalidVersion` if some version
+// is not positive and `ErrorNilApplyFunc` if some `up` function is nil.
+func FromNamedMap(m map[int64]NamedApplyFuncs) ([]Migration, error) {
+	migrations := make([]Migration, 0, len(m))
+
+	for version, funcs := range m {
+		if version <= 0 {
+			return nil, fmt.Errorf("%w: %d", ErrorInvalidVersion, version)
+		}
+
+		if funcs.Up == nil {
+			return nil, fmt.Errorf("%w: version %d", ErrorNilApplyFunc, version)
+		}
+
+		migrations = append(migrations, Migration{Version: version, Name: funcs.Name, Up: funcs.Up, Down: funcs.Down})
+	}
+
+	sort.Sort(Migrations(migrations))
+
+	return migrations, nil
+}
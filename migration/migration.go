@@ -1,17 +1,146 @@
 package migration
 
-import "gorm.io/gorm"
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // ApplyFunc declares func type for migration functions.
 type ApplyFunc func(db *gorm.DB) error
 
-// Migration declares a migration data structure.
+// ApplyFuncWithContext declares func type for context-aware migration functions.
+type ApplyFuncWithContext func(ctx context.Context, db *gorm.DB) error
+
+// RetryPolicy declares how many times a failed migration function is called again. The delay before
+// the n-th retry is `BackoffBase * 2^(n-1)`. A zero policy means no retries.
+type RetryPolicy struct {
+	MaxAttempts int
+	BackoffBase time.Duration
+}
+
+// Migration declares a migration data structure. It implements GORM `BeforeCreate` and `AfterCreate` hooks
+// which are called when a history record is inserted (see `RegisterAuditFunc` and `NotifyCreated`).
 type Migration struct {
-	Version int64     `gorm:"primaryKey"`
-	Name    string    `gorm:"name"`
-	Up      ApplyFunc `gorm:"-"`
-	Down    ApplyFunc `gorm:"-"`
-	Stored  bool      `gorm:"-"`
+	ID                  int64 `gorm:"primaryKey"`
+	Version             int64
+	Name                string               `gorm:"name"`
+	Up                  ApplyFunc            `gorm:"-"`
+	Down                ApplyFunc            `gorm:"-"`
+	UpCtx               ApplyFuncWithContext `gorm:"-"`
+	DownCtx             ApplyFuncWithContext `gorm:"-"`
+	PostUp              ApplyFunc            `gorm:"-"`
+	PostDown            ApplyFunc            `gorm:"-"`
+	UpSQL               string               `gorm:"-"`
+	DownSQL             string               `gorm:"-"`
+	Stored              bool                 `gorm:"-"`
+	NoTransaction       bool                 `gorm:"-"`
+	Tags                []string             `gorm:"-"`
+	RetryPolicy         RetryPolicy          `gorm:"-"`
+	Timeout             time.Duration        `gorm:"-"`
+	DependsOn           []int64              `gorm:"-"`
+	SemanticVersion     string               `gorm:"-"`
+	Group               string               `gorm:"-"`
+	AppliedAt           time.Time            `gorm:"autoCreateTime"`
+	ExecutionDurationMs int64                `gorm:"column:execution_duration_ms"`
+	Checksum            string               `gorm:"size:64"`
+	AppliedDirection    string               `gorm:"size:4"`
+	AppliedBy           string               `gorm:"size:255"`
+	AppliedHost         string               `gorm:"size:255"`
+}
+
+// NewSQLMigration returns a new migration which executes SQL statements.
+func NewSQLMigration(version int64, name, upSQL, downSQL string) Migration {
+	return Migration{Version: version, Name: name, UpSQL: upSQL, DownSQL: downSQL}
+}
+
+// ApplyUp calls `UpCtx` if it is defined, `Up` if it is defined or executes `UpSQL` otherwise.
+// A migration without `up` function and SQL statement does nothing.
+func (mig *Migration) ApplyUp(ctx context.Context, db *gorm.DB) error {
+	if mig.UpCtx != nil {
+		return mig.UpCtx(ctx, db)
+	}
+
+	if mig.Up == nil && mig.UpSQL != "" {
+		return execSQL(db, mig.UpSQL)
+	}
+
+	return safeApply(mig.Up, db)
+}
+
+// ApplyDown calls `DownCtx` if it is defined, `Down` if it is defined or executes `DownSQL` otherwise.
+// A migration without `down` function and SQL statement does nothing.
+func (mig *Migration) ApplyDown(ctx context.Context, db *gorm.DB) error {
+	if mig.DownCtx != nil {
+		return mig.DownCtx(ctx, db)
+	}
+
+	if mig.Down == nil && mig.DownSQL != "" {
+		return execSQL(db, mig.DownSQL)
+	}
+
+	return safeApply(mig.Down, db)
+}
+
+// execSQL executes the SQL statement. It returns `ErrorNoDB` if the DB instance is nil.
+func execSQL(db *gorm.DB, sql string) error {
+	if db == nil {
+		return ErrorNoDB
+	}
+
+	return db.Exec(sql).Error
+}
+
+// safeApply calls the function if it is not nil. A nil function is a no-op like `DummyUpDown`.
+func safeApply(fn ApplyFunc, db *gorm.DB) error {
+	if fn == nil {
+		return nil
+	}
+
+	return fn(db)
+}
+
+// HasUp returns `true` if the migration has an `up` function or an SQL statement.
+func (mig *Migration) HasUp() bool {
+	return mig.Up != nil || mig.UpCtx != nil || mig.UpSQL != ""
+}
+
+// HasDown returns `true` if the migration has a `down` function or an SQL statement.
+func (mig *Migration) HasDown() bool {
+	return mig.Down != nil || mig.DownCtx != nil || mig.DownSQL != ""
+}
+
+// HasAnyTag returns `true` if the migration has at least one of the specified tags.
+func (mig *Migration) HasAnyTag(tags []string) bool {
+	for _, tag := range mig.Tags {
+		for _, t := range tags {
+			if tag == t {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ComputeChecksum returns SHA-256 hash of the canonical representation of the migration.
+// Migration functions are not included since their addresses are unstable.
+func (mig *Migration) ComputeChecksum() string {
+	canonical := strconv.FormatInt(mig.Version, 10) + "\n" + mig.Name
+
+	if mig.UpSQL != "" || mig.DownSQL != "" {
+		canonical += "\n" + mig.UpSQL + "\n" + mig.DownSQL
+	}
+
+	sum := sha256.Sum256([]byte(canonical))
+
+	return hex.EncodeToString(sum[:])
 }
 
 // Less returns `true` if an argument is more than current.
@@ -39,6 +168,150 @@ func (ms Migrations) Less(i int, j int) bool {
 	return CompareMigrations(&ms[i], &ms[j])
 }
 
+// Find returns the migration with the specified version and `true` or a zero value and `false` if there is
+// no such migration. The slice must be sorted by version.
+func Find(migrations []Migration, version int64) (Migration, bool) {
+	i := sort.Search(len(migrations), func(i int) bool {
+		return migrations[i].Version >= version
+	})
+
+	if i < len(migrations) && migrations[i].Version == version {
+		return migrations[i], true
+	}
+
+	return Migration{}, false
+}
+
+// Contains returns `true` if the sorted slice contains a migration with the specified version.
+func (ms Migrations) Contains(version int64) bool {
+	_, ok := Find(ms, version)
+
+	return ok
+}
+
+// Filter returns a new slice of migrations with versions in the range [minVersion, maxVersion].
+// The slice must be sorted by version.
+func (ms Migrations) Filter(minVersion, maxVersion int64) Migrations {
+	from := sort.Search(len(ms), func(i int) bool {
+		return ms[i].Version >= minVersion
+	})
+	to := sort.Search(len(ms), func(i int) bool {
+		return ms[i].Version > maxVersion
+	})
+
+	if from >= to {
+		return Migrations{}
+	}
+
+	filtered := make(Migrations, to-from)
+	copy(filtered, ms[from:to])
+
+	return filtered
+}
+
+// Before returns a new slice of migrations with versions less than the specified one.
+func (ms Migrations) Before(version int64) Migrations {
+	if version == math.MinInt64 {
+		return Migrations{}
+	}
+
+	return ms.Filter(math.MinInt64, version-1)
+}
+
+// After returns a new slice of migrations with versions greater than the specified one.
+func (ms Migrations) After(version int64) Migrations {
+	if version == math.MaxInt64 {
+		return Migrations{}
+	}
+
+	return ms.Filter(version+1, math.MaxInt64)
+}
+
+// Versions returns a sorted slice of the versions of the migrations.
+func (ms Migrations) Versions() []int64 {
+	versions := make([]int64, len(ms))
+
+	for i, mig := range ms {
+		versions[i] = mig.Version
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i] < versions[j]
+	})
+
+	return versions
+}
+
+// MaxVersion returns the highest version of the migrations or zero if the slice is empty.
+func (ms Migrations) MaxVersion() (version int64) {
+	for i, mig := range ms {
+		if i == 0 || mig.Version > version {
+			version = mig.Version
+		}
+	}
+
+	return
+}
+
+// MinVersion returns the lowest version of the migrations or zero if the slice is empty.
+func (ms Migrations) MinVersion() (version int64) {
+	for i, mig := range ms {
+		if i == 0 || mig.Version < version {
+			version = mig.Version
+		}
+	}
+
+	return
+}
+
+// Equal returns `true` if both slices have the same length and corresponding migrations have equal versions,
+// names and `Stored` flags. Functions are not compared.
+func (ms Migrations) Equal(other Migrations) bool {
+	if len(ms) != len(other) {
+		return false
+	}
+
+	for i := range ms {
+		if ms[i].Version != other[i].Version || ms[i].Name != other[i].Name || ms[i].Stored != other[i].Stored {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Diff compares migrations with the other ones by version. It returns migrations which are absent in the other slice
+// as `added`, migrations of the other slice which are absent in this one as `removed` and migrations which are
+// present in both slices with different names as `modified` (the entries of this slice are returned).
+// Results keep the order of the source slices.
+func (ms Migrations) Diff(other Migrations) (added, removed, modified []Migration) {
+	names := make(map[int64]string, len(other))
+
+	for _, mig := range other {
+		names[mig.Version] = mig.Name
+	}
+
+	versions := make(map[int64]bool, len(ms))
+
+	for _, mig := range ms {
+		versions[mig.Version] = true
+
+		if name, ok := names[mig.Version]; !ok {
+			added = append(added, mig)
+		} else if name != mig.Name {
+			modified = append(modified, mig)
+		}
+	}
+
+	for _, mig := range other {
+		if !versions[mig.Version] {
+			removed = append(removed, mig)
+		}
+	}
+
+	return
+}
+
 // CompareMigrations compares two migrations and returns `true` if `left` migration is less.
 func CompareMigrations(left *Migration, right *Migration) bool {
 	return left.Version < right.Version
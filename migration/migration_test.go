@@ -0,0 +1,107 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestApplyNilFunctions(t *testing.T) {
+	errApply := errors.New("applied")
+	fn := func(db *gorm.DB) error { return errApply }
+
+	tests := []struct {
+		name     string
+		migr     Migration
+		wantUp   error
+		wantDown error
+	}{
+		{"both nil", Migration{Version: 2}, nil, nil},
+		{"nil up", Migration{Version: 2, Down: fn}, nil, errApply},
+		{"nil down", Migration{Version: 2, Up: fn}, errApply, nil},
+		{"both set", Migration{Version: 2, Up: fn, Down: fn}, errApply, errApply},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.migr.ApplyUp(context.Background(), nil); err != tt.wantUp {
+				t.Errorf("ApplyUp() error = %v, want %v", err, tt.wantUp)
+			}
+
+			if err := tt.migr.ApplyDown(context.Background(), nil); err != tt.wantDown {
+				t.Errorf("ApplyDown() error = %v, want %v", err, tt.wantDown)
+			}
+		})
+	}
+}
+
+func TestApplySQLWithoutDB(t *testing.T) {
+	migr := NewSQLMigration(2, "sql", "SELECT 1", "SELECT 2")
+
+	if err := migr.ApplyUp(context.Background(), nil); !errors.Is(err, ErrorNoDB) {
+		t.Errorf("ApplyUp() error = %v, want %v", err, ErrorNoDB)
+	}
+}
+
+func TestMigrationsDiff(t *testing.T) {
+	mig := func(version int64, name string) Migration {
+		return Migration{Version: version, Name: name}
+	}
+
+	tests := []struct {
+		name     string
+		ms       Migrations
+		other    Migrations
+		added    []int64
+		removed  []int64
+		modified []int64
+	}{
+		{"equal", Migrations{mig(1, "-"), mig(2, "a")}, Migrations{mig(2, "a"), mig(1, "-")}, nil, nil, nil},
+		{"empty", nil, nil, nil, nil, nil},
+		{"added", Migrations{mig(3, "c"), mig(1, "-"), mig(2, "b")}, Migrations{mig(1, "-")}, []int64{3, 2}, nil, nil},
+		{"removed", Migrations{mig(1, "-")}, Migrations{mig(4, "d"), mig(1, "-"), mig(2, "b")}, nil, []int64{4, 2}, nil},
+		{"modified", Migrations{mig(2, "b"), mig(3, "c")}, Migrations{mig(2, "b"), mig(3, "x")}, nil, nil, []int64{3}},
+		{
+			"mixed",
+			Migrations{mig(1, "-"), mig(2, "renamed"), mig(4, "d")},
+			Migrations{mig(1, "-"), mig(2, "b"), mig(3, "c")},
+			[]int64{4}, []int64{3}, []int64{2},
+		},
+	}
+
+	versions := func(ms []Migration) (versions []int64) {
+		for _, mig := range ms {
+			versions = append(versions, mig.Version)
+		}
+
+		return
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, removed, modified := tt.ms.Diff(tt.other)
+
+			if got := versions(added); !reflect.DeepEqual(got, tt.added) {
+				t.Errorf("added = %v, want %v", got, tt.added)
+			}
+
+			if got := versions(removed); !reflect.DeepEqual(got, tt.removed) {
+				t.Errorf("removed = %v, want %v", got, tt.removed)
+			}
+
+			if got := versions(modified); !reflect.DeepEqual(got, tt.modified) {
+				t.Errorf("modified = %v, want %v", got, tt.modified)
+			}
+		})
+	}
+
+	// the entries of the source slice are returned as modified
+	_, _, modified := Migrations{mig(2, "new")}.Diff(Migrations{mig(2, "old")})
+
+	if len(modified) != 1 || modified[0].Name != "new" {
+		t.Errorf("modified = %+v, want the migration named new", modified)
+	}
+}
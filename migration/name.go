@@ -0,0 +1,24 @@
+package migration
+
+// MaxNameLength is the maximum length of a sanitized migration name.
+const MaxNameLength = 255
+
+// SanitizeName replaces characters other than latin letters, digits and underscores with underscores
+// and truncates the name to `MaxNameLength` characters.
+func SanitizeName(s string) string {
+	sanitized := make([]byte, 0, len(s))
+
+	for _, r := range s {
+		if len(sanitized) == MaxNameLength {
+			break
+		}
+
+		if r == '_' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+			sanitized = append(sanitized, byte(r))
+		} else {
+			sanitized = append(sanitized, '_')
+		}
+	}
+
+	return string(sanitized)
+}
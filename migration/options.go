@@ -0,0 +1,46 @@
+package migration
+
+import "time"
+
+// MigrationOption declares a func type which modifies a migration.
+type MigrationOption func(m *Migration)
+
+// New returns a new migration modified by the options.
+func New(version int64, name string, up, down ApplyFunc, opts ...MigrationOption) Migration {
+	mig := Migration{Version: version, Name: name, Up: up, Down: down}
+
+	for _, opt := range opts {
+		opt(&mig)
+	}
+
+	return mig
+}
+
+// WithRetry sets the retry policy of the migration.
+func WithRetry(p RetryPolicy) MigrationOption {
+	return func(m *Migration) {
+		m.RetryPolicy = p
+	}
+}
+
+// WithTimeout sets the maximum duration of the migration including retries. Zero means no limit.
+func WithTimeout(d time.Duration) MigrationOption {
+	return func(m *Migration) {
+		m.Timeout = d
+	}
+}
+
+// WithTag appends tags to the migration.
+func WithTag(tags ...string) MigrationOption {
+	return func(m *Migration) {
+		m.Tags = append(m.Tags, tags...)
+	}
+}
+
+// WithGroup sets the group of the migration. Consecutive pending migrations of the same group are applied
+// atomically if the migrator enables grouped migrations.
+func WithGroup(group string) MigrationOption {
+	return func(mig *Migration) {
+		mig.Group = group
+	}
+}
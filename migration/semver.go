@@ -0,0 +1,58 @@
+package migration
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// semanticBase is the multiplier of semantic version components in integer versions.
+const semanticBase = 1000
+
+// ErrorInvalidSemanticVersion means that the string does not correspond to `major.minor.patch`.
+const ErrorInvalidSemanticVersion = Error("Invalid semantic version")
+
+// ParseSemanticVersion parses the `major.minor.patch` string with an optional `v` prefix. Minor and patch
+// components must be less than 1000 to be encoded by `SemanticToInt64`.
+func ParseSemanticVersion(s string) (major, minor, patch int, err error) {
+	parts := strings.Split(strings.TrimPrefix(s, "v"), ".")
+
+	if len(parts) != 3 {
+		err = fmt.Errorf("%w: %s", ErrorInvalidSemanticVersion, s)
+		return
+	}
+
+	components := make([]int, 3)
+
+	for i, part := range parts {
+		if components[i], err = strconv.Atoi(part); err != nil || components[i] < 0 ||
+			(i > 0 && components[i] >= semanticBase) {
+			err = fmt.Errorf("%w: %s", ErrorInvalidSemanticVersion, s)
+			return
+		}
+	}
+
+	major, minor, patch = components[0], components[1], components[2]
+
+	return
+}
+
+// SemanticToInt64 encodes the semantic version as `major*1_000_000 + minor*1_000 + patch`.
+func SemanticToInt64(major, minor, patch int) int64 {
+	return (int64(major)*semanticBase+int64(minor))*semanticBase + int64(patch)
+}
+
+// FormatSemanticVersion decodes the version encoded by `SemanticToInt64` to the `major.minor.patch` string.
+func FormatSemanticVersion(version int64) string {
+	return fmt.Sprintf("%d.%d.%d", version/(semanticBase*semanticBase), version/semanticBase%semanticBase,
+		version%semanticBase)
+}
+
+// Semantic returns the semantic version of the migration or the decoded version if it is not set.
+func (mig *Migration) Semantic() string {
+	if mig.SemanticVersion != "" {
+		return mig.SemanticVersion
+	}
+
+	return FormatSemanticVersion(mig.Version)
+}
@@ -0,0 +1,38 @@
+package migration
+
+import "gorm.io/gorm"
+
+// TestMigration declares a recorder of migration function calls for tests. `UpError` and `DownError`
+// are returned by the corresponding functions.
+type TestMigration struct {
+	UpCalled      bool
+	DownCalled    bool
+	UpCallCount   int
+	DownCallCount int
+	UpError       error
+	DownError     error
+}
+
+// NewTestMigration returns a new recorder and a migration whose functions record calls on it.
+func NewTestMigration(version int64, name string) (*TestMigration, Migration) {
+	tm := &TestMigration{}
+
+	mig := Migration{
+		Version: version,
+		Name:    name,
+		Up: func(db *gorm.DB) error {
+			tm.UpCalled = true
+			tm.UpCallCount++
+
+			return tm.UpError
+		},
+		Down: func(db *gorm.DB) error {
+			tm.DownCalled = true
+			tm.DownCallCount++
+
+			return tm.DownError
+		},
+	}
+
+	return tm, mig
+}
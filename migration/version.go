@@ -0,0 +1,35 @@
+package migration
+
+import (
+	"strconv"
+	"time"
+)
+
+// TimestampVersionLayout is the layout of 14-digit timestamp versions (`YYYYMMDDHHmmSS`).
+const TimestampVersionLayout = "20060102150405"
+
+// NextVersion returns the highest version of the migrations plus one. Version 1 is reserved for the initial
+// migration, so 2 is returned for an empty list.
+func NextVersion(migrations []Migration) int64 {
+	var max int64 = 1
+
+	for _, mig := range migrations {
+		if mig.Version > max {
+			max = mig.Version
+		}
+	}
+
+	return max + 1
+}
+
+// NextTimestampVersion returns the current UTC time as a 14-digit version (`YYYYMMDDHHmmSS`).
+func NextTimestampVersion() int64 {
+	version, _ := strconv.ParseInt(time.Now().UTC().Format(TimestampVersionLayout), 10, 64)
+
+	return version
+}
+
+// NewSequential returns a new migration with the next version of the existing migrations.
+func NewSequential(name string, up, down ApplyFunc, existing []Migration) Migration {
+	return Migration{Version: NextVersion(existing), Name: name, Up: up, Down: down}
+}
@@ -1,166 +1,1075 @@
 package migrator
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
 	"sort"
 	"strconv"
+	"sync"
+	"time"
 
 	"gorm.io/gorm"
 
 	"github.com/Devoter/gorm-migrator/migration"
 )
 
-// Migrator declares GORM migrations manager.
+const (
+	// envTargetVersion is the suffix of the environment variable with the target version of `up` command.
+	envTargetVersion = "TARGET_VERSION"
+	// envSetVersion is the suffix of the environment variable with the version of `set_version` command.
+	envSetVersion = "SET_VERSION"
+)
+
+// Migrator declares GORM migrations manager. It is safe for concurrent use: operations which change the database
+// revision (`Init`, `Up`, `Down`, `Reset`, `SetVersion`, etc.) are serialized, while read-only operations
+// (`Version`, `Status`, `Pending`, `History`, etc.) may run concurrently with each other. Copies of the migrator
+// returned by `Copy` are synchronized independently. Use `WithLockStrategy` to prevent concurrent runs
+// by different processes.
 type Migrator struct {
 	db         *gorm.DB
+	store      MigrationStore
 	migrations []migration.Migration
+	config     Config
+	autoInit   *autoInitState
+	skipped    *skippedState
+	mu         *sync.RWMutex
+}
+
+// autoInitState declares a state of the automatic initialization shared by copies of the migrator.
+type autoInitState struct {
+	sync.Mutex
+	done bool
+}
+
+// NewMigrator returns a new instance of Migrator which keeps the history in the database table.
+func NewMigrator(db *gorm.DB, migrations []migration.Migration, opts ...MigratorOption) *Migrator {
+	config := newConfig(opts...)
+	store := NewGORMStore(db, config.qualifiedTableName())
+	store.skipAutoMigrate = !config.AutoMigrate
+
+	return newMigrator(db, store, migrations, config)
+}
+
+// NewMigratorWithStore returns a new instance of Migrator which keeps the history in the specified store.
+// If the store is not a GORMStore, migrations receive the DB instance set by `WithMigrationDB`. Without it
+// they receive a nil DB instance, are never run inside transactions and SQL migrations fail with
+// `migration.ErrorNoDB`.
+func NewMigratorWithStore(store MigrationStore, migrations []migration.Migration, opts ...MigratorOption) *Migrator {
+	config := newConfig(opts...)
+	db := config.MigrationDB
+
+	if s, ok := store.(*GORMStore); ok {
+		db = s.db
+	}
+
+	return newMigrator(db, store, migrations, config)
+}
+
+func newMigrator(db *gorm.DB, store MigrationStore, migrations []migration.Migration, config Config) *Migrator {
+	if len(config.DefaultTags) > 0 {
+		migrations = filterByTags(migrations, config.DefaultTags)
+	}
+
+	// migrations are kept sorted by version to look them up, plans are ordered by dependencies
+	all := append(migrations, migration.DummySQLMigration(1, "-"))
+	sort.Sort(migration.Migrations(all))
+
+	m := &Migrator{db: db, store: store, migrations: all, config: config, autoInit: &autoInitState{},
+		skipped: &skippedState{}, mu: &sync.RWMutex{}}
+
+	if err := m.validateVersions(); err != nil {
+		m.config.Logger.Error("Invalid migration version", err)
+	}
+
+	if err := migration.DetectCycles(all); err != nil {
+		m.config.Logger.Error("Invalid migration dependencies", err)
+	}
+
+	if len(config.SkipVersions) > 0 {
+		m.config.Logger.Info("WARNING: migration versions are going to be skipped, their migrations are never applied",
+			"versions", config.SkipVersions)
+	}
+
+	return m
+}
+
+// Run interprets commands. The `--dry-run` option enables the dry run mode (see `WithDryRun`) for the command,
+// `init` command does not support it.
+func (m *Migrator) Run(ctx context.Context, args ...string) (oldVersion int64, newVersion int64, err error) {
+	var command string
+	var opts map[string]string
+
+	if command, opts, err = ParseArgs(args); err != nil {
+		return
+	}
+
+	if opts[ArgDryRun] == "true" {
+		if command == "init" {
+			err = ErrorUnexpectedCommand
+			return
+		}
+
+		m = m.withDryRun()
+	}
+
+	if tags, ok := opts[ArgTags]; ok {
+		return m.withTags(splitTags(tags)).run(ctx, command, opts)
+	}
+
+	return m.run(ctx, command, opts)
+}
+
+// run interprets the parsed command.
+func (m *Migrator) run(ctx context.Context, command string, opts map[string]string) (oldVersion int64, newVersion int64, err error) {
+	switch command {
+	case "init":
+		return m.Init(ctx)
+	case "up":
+		var target int64
+
+		if target, err = m.parseVersion(false, envTargetVersion, versionArgs(opts)...); err != nil {
+			return
+		}
+
+		return m.Up(ctx, target)
+	case "down":
+		var target int64
+
+		if target, err = m.parseVersion(false, "", versionArgs(opts)...); err != nil {
+			return
+		}
+
+		if target == -1 {
+			return m.Down(ctx)
+		}
+
+		return m.DownTo(ctx, target)
+	case "reset":
+		return m.Reset(ctx)
+	case "down_all":
+		return m.DownAll(ctx)
+	case "refresh":
+		return m.Refresh(ctx)
+	case "baseline":
+		var target int64
+
+		if target, err = m.parseVersion(false, "", versionArgs(opts)...); err != nil {
+			return
+		}
+
+		return m.Baseline(ctx, target)
+	case "version":
+		return m.runVersion(ctx)
+	case "repair":
+		return m.runRepair(ctx, m.config.DryRun)
+	case "set_version":
+		var target int64
+
+		if target, err = m.parseVersion(true, envSetVersion, versionArgs(opts)...); err != nil {
+			return
+		}
+
+		return m.SetVersion(ctx, target)
+	default:
+		err = ErrorUnexpectedCommand
+		return
+	}
+}
+
+// Init creates `migrations` table if it does not exist and records the initial zero-migration if it is missing.
+// If the table already exists its schema is upgraded to the actual one. It returns `ErrorAlreadyInitialized`
+// if the initial zero-migration is already recorded. The dialect initialization (see `WithDialectInit`)
+// is performed before the table is created.
+func (m *Migrator) Init(ctx context.Context) (oldVersion int64, newVersion int64, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	err = m.createTable(ctx)
+
+	return
+}
+
+// createTable is like `Init` but does not hold the migrator lock.
+func (m *Migrator) createTable(ctx context.Context) (err error) {
+	if err = m.waitForDB(ctx); err != nil {
+		return
+	}
+
+	migr := migration.Migration{Version: 1, Name: "-", AppliedBy: SystemAppliedBy, AppliedHost: m.config.AppliedHost}
+	migr.Checksum = migr.ComputeChecksum()
+	db := m.session(ctx)
+	store := m.historyStore(db)
+
+	if err = m.dialectInit(db); err != nil {
+		return
+	}
+
+	if err = store.CreateTable(); err != nil {
+		return
+	}
+
+	var history []migration.Migration
+
+	if history, err = store.ListMigrations(); err != nil {
+		return
+	}
+
+	if migration.Migrations(history).Contains(migr.Version) {
+		err = ErrorAlreadyInitialized
+		return
+	}
+
+	err = store.InsertMigration(migr)
+
+	return
+}
+
+// InitIfNotExists is like `Init` but treats already initialized migrations as a success. It returns
+// `initialized = true` and the initial version as the new one only if the migrations table was created
+// by this call. Otherwise it returns `initialized = false` and the current version. Stores which can not check
+// the existence of the table (other than GORMStore) are considered initialized if the initial zero-migration
+// was recorded by this call.
+func (m *Migrator) InitIfNotExists(ctx context.Context) (oldVersion int64, newVersion int64, initialized bool, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err = m.waitForDB(ctx); err != nil {
+		return
+	}
+
+	checker, canCheck := m.historyStore(m.session(ctx)).(tableChecker)
+	existed := canCheck && checker.HasTable()
+
+	if err = m.createTable(ctx); err != nil && err != ErrorAlreadyInitialized {
+		return
+	}
+
+	if canCheck {
+		initialized = !existed
+	} else {
+		initialized = err == nil
+	}
+
+	if initialized {
+		return 0, 1, true, nil
+	}
+
+	oldVersion, newVersion, err = m.version(ctx)
+
+	return
+}
+
+// init is like `Init` but treats `ErrorAlreadyInitialized` as a success.
+func (m *Migrator) init(ctx context.Context) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.initTable(ctx)
+}
+
+// initTable is like `init` but does not hold the migrator lock.
+func (m *Migrator) initTable(ctx context.Context) (err error) {
+	if err = m.createTable(ctx); err == ErrorAlreadyInitialized {
+		err = nil
+	}
+
+	return
+}
+
+// Up upgrades database revision to the target or next version.
+func (m *Migrator) Up(ctx context.Context, target int64) (oldVersion int64, newVersion int64, err error) {
+	res, err := m.UpResult(ctx, target)
+
+	return res.OldVersion, res.NewVersion, err
+}
+
+// UpResult upgrades database revision to the target or next version and returns the detailed result.
+// Applied migrations of the result are marked as stored and contain their history fields.
+func (m *Migrator) UpResult(ctx context.Context, target int64) (res Result, err error) {
+	if err = m.waitForDB(ctx); err != nil {
+		return
+	}
+
+	err = m.withLock(ctx, func() (err error) {
+		res, err = m.up(ctx, target, -1)
+		return
+	})
+
+	return
+}
+
+// UpN applies at most `n` pending migrations.
+func (m *Migrator) UpN(ctx context.Context, n int) (oldVersion int64, newVersion int64, err error) {
+	res, err := m.UpNResult(ctx, n)
+
+	return res.OldVersion, res.NewVersion, err
+}
+
+// UpNResult applies at most `n` pending migrations and returns the detailed result.
+func (m *Migrator) UpNResult(ctx context.Context, n int) (res Result, err error) {
+	if n <= 0 {
+		err = ErrorInvalidStepCount
+		return
+	}
+
+	err = m.withLock(ctx, func() (err error) {
+		res, err = m.up(ctx, -1, n)
+		return
+	})
+
+	return
+}
+
+// up applies pending migrations up to the target version. A negative `limit` means no limit of applied migrations count.
+func (m *Migrator) up(ctx context.Context, target int64, limit int) (res Result, err error) {
+	var plan []migration.Migration
+	db := m.session(ctx)
+
+	var skipped []int64
+
+	if res, plan, skipped, err = m.planUp(db, target, limit); err != nil {
+		return
+	}
+
+	m.setSkipped(skipped)
+
+	if m.config.DryRun {
+		return
+	}
+
+	return m.applyPlan(ctx, db, res, plan, true)
+}
+
+// applyPlan applies `up` functions of the migrations of the plan in its order. If `record` is `false`
+// the history table is not updated. Errors are collected into `MultiError` if the error strategy
+// is `ContinueOnError`, migrations which depend on failed ones are not applied and reported as `DependencyError`.
+// Applied migrations are rolled back on error if it is enabled by `WithRollbackOnError`.
+func (m *Migrator) applyPlan(ctx context.Context, db *gorm.DB, planned Result, plan []migration.Migration, record bool) (res Result, err error) {
+	res = Result{OldVersion: planned.OldVersion, NewVersion: planned.OldVersion, Skipped: planned.Skipped}
+
+	var errs []error
+
+	// versions which are not applied because of errors, the plan follows dependencies, so dependents
+	// of failed migrations are marked as failed transitively
+	failed := map[int64]bool{}
+
+	m.config.Hooks.beforeAll(DirectionUp)
+
+	for i := 0; i < len(plan); {
+		n := m.groupLength(db, plan[i:])
+		group := plan[i : i+n]
+		i += n
+
+		if depErrs := failedDependencies(group, failed); len(depErrs) > 0 {
+			errs = append(errs, depErrs...)
+			continue
+		}
+
+		applied, stepErr := m.applyGroup(ctx, db, group, record)
+
+		for _, migr := range applied {
+			// pending migrations may be older than the current version, e.g. after skipped ones
+			if migr.Version > res.NewVersion {
+				res.NewVersion = migr.Version
+			}
+
+			res.Applied = append(res.Applied, migr)
+		}
+
+		if stepErr != nil {
+			if m.config.ErrorStrategy != ContinueOnError {
+				err = stepErr
+				break
+			}
+
+			errs = append(errs, stepErr)
+
+			for _, migr := range group[len(applied):] {
+				failed[migr.Version] = true
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		err = &MultiError{errors: errs}
+	}
+
+	if err != nil && m.config.RollbackOnError {
+		res, err = m.rollbackApplied(ctx, db, res, err, record)
+	}
+
+	m.config.Hooks.afterAll(DirectionUp, err)
+
+	return
+}
+
+// failedDependencies returns errors of migrations of the group which depend on failed versions. If the group
+// can not be applied, all its migrations are marked as failed, because groups are applied atomically.
+func failedDependencies(group []migration.Migration, failed map[int64]bool) (errs []error) {
+	for _, migr := range group {
+		for _, dep := range migr.DependsOn {
+			if failed[dep] {
+				errs = append(errs, &DependencyError{Version: migr.Version, DependsOn: dep})
+				break
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		for _, migr := range group {
+			failed[migr.Version] = true
+		}
+	}
+
+	return
+}
+
+// isGrouped returns `true` if the migration is a member of a group which is applied atomically.
+func (m *Migrator) isGrouped(db *gorm.DB, migr *migration.Migration) bool {
+	return m.config.GroupedMigrations && db != nil && migr.Group != ""
+}
+
+// groupLength returns the number of leading migrations of the plan which must be applied together: the length
+// of the run of migrations with the same group if the first migration is grouped and one otherwise.
+func (m *Migrator) groupLength(db *gorm.DB, plan []migration.Migration) int {
+	if !m.isGrouped(db, &plan[0]) {
+		return 1
+	}
+
+	n := 1
+
+	for n < len(plan) && plan[n].Group == plan[0].Group {
+		n++
+	}
+
+	return n
+}
+
+// applyGroup applies `up` functions of the migrations in their order and returns the applied ones. Migrations
+// of a group are applied inside a single transaction which is rolled back on error, so none of them is applied.
+func (m *Migrator) applyGroup(ctx context.Context, db *gorm.DB, group []migration.Migration, record bool) (applied []migration.Migration, err error) {
+	if !m.isGrouped(db, &group[0]) {
+		migr := group[0]
+
+		if err = m.applyStep(ctx, db, &migr, record); err == nil || m.isAppliedDespite(db, &migr, err) {
+			applied = append(applied, migr)
+		}
+
+		return
+	}
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, migr := range group {
+			if err := m.applyStep(ctx, tx, &migr, record); err != nil {
+				return err
+			}
+
+			applied = append(applied, migr)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		m.config.Logger.Info("Migration group rolled back", "group", group[0].Group, "migrations", len(group))
+		applied = nil
+	}
+
+	return
+}
+
+// applyStep checks the migration for no-op `up` function and applies it.
+func (m *Migrator) applyStep(ctx context.Context, db *gorm.DB, migr *migration.Migration, record bool) error {
+	if err := m.checkNoop(migr); err != nil {
+		return err
+	}
+
+	return m.step(ctx, db, migr, DirectionUp, record)
+}
+
+// rollbackApplied rolls the applied migrations of the result back in reverse order after the `up` error.
+// It stops on the first rollback failure and returns `RollbackError` which wraps both errors.
+func (m *Migrator) rollbackApplied(ctx context.Context, db *gorm.DB, applied Result, upErr error, record bool) (res Result, err error) {
+	res = applied
+	err = upErr
+
+	for i := len(res.Applied) - 1; i >= 0; i-- {
+		migr := res.Applied[i]
+
+		if rollbackErr := m.step(ctx, db, &migr, DirectionDown, record); rollbackErr != nil {
+			err = &RollbackError{Err: upErr, RollbackErr: rollbackErr}
+			return
+		}
+
+		res.Applied = res.Applied[:i]
+		res.NewVersion = res.OldVersion
+
+		for _, applied := range res.Applied {
+			if applied.Version > res.NewVersion {
+				res.NewVersion = applied.Version
+			}
+		}
+	}
+
+	return
+}
+
+// checkNoop reports a migration except the initial one which uses `DummyUpDown` as its `up` function.
+// It returns `ErrorNoopMigration` wrapped into `VersionError` if the strict check is enabled and logs a warning
+// otherwise.
+func (m *Migrator) checkNoop(migr *migration.Migration) error {
+	if migr.Version == 1 || !migration.IsDummyUpDown(migr.Up) {
+		return nil
+	}
+
+	if m.config.StrictNoopCheck {
+		return &VersionError{Version: migr.Version, Err: ErrorNoopMigration}
+	}
+
+	m.config.Logger.Info("Migration does nothing", "version", migr.Version, "name", migr.Name)
+
+	return nil
+}
+
+// UpFrom applies migrations with versions in the range (fromVersion, toVersion] regardless of the history.
+// The history table is not updated, so the method can be used e.g. to replay migrations onto a replica.
+func (m *Migrator) UpFrom(ctx context.Context, fromVersion, toVersion int64) (oldVersion int64, newVersion int64, err error) {
+	if fromVersion >= toVersion {
+		err = ErrorInvalidVersionRange
+		return
+	}
+
+	planned := Result{OldVersion: fromVersion, NewVersion: fromVersion}
+	plan := migration.Migrations(m.migrations).Filter(fromVersion+1, toVersion)
+
+	if len(plan) > 0 {
+		planned.NewVersion = plan[len(plan)-1].Version
+	}
+
+	if m.config.DryRun {
+		return planned.OldVersion, planned.NewVersion, nil
+	}
+
+	err = m.withLock(ctx, func() (err error) {
+		var res Result
+
+		res, err = m.applyPlan(ctx, m.session(ctx), planned, plan, false)
+		oldVersion = res.OldVersion
+		newVersion = res.NewVersion
+
+		return
+	})
+
+	return
+}
+
+// Down downgrades database revision to the previous version.
+func (m *Migrator) Down(ctx context.Context) (oldVersion int64, newVersion int64, err error) {
+	res, err := m.DownResult(ctx)
+
+	return res.OldVersion, res.NewVersion, err
+}
+
+// DownResult downgrades database revision to the previous version and returns the detailed result.
+func (m *Migrator) DownResult(ctx context.Context) (res Result, err error) {
+	err = m.withLock(ctx, func() (err error) {
+		res, err = m.down(ctx)
+		return
+	})
+
+	return
+}
+
+func (m *Migrator) down(ctx context.Context) (res Result, err error) {
+	var plan []migration.Migration
+	db := m.session(ctx)
+
+	if res, plan, err = m.planDown(db); err != nil || m.config.DryRun {
+		return
+	}
+
+	return m.rollback(ctx, db, res, plan)
+}
+
+// DownN downgrades database revision by `n` versions. The initial version is never rolled back.
+func (m *Migrator) DownN(ctx context.Context, n int) (oldVersion int64, newVersion int64, err error) {
+	res, err := m.DownNResult(ctx, n)
+
+	return res.OldVersion, res.NewVersion, err
+}
+
+// DownNResult downgrades database revision by `n` versions and returns the detailed result.
+func (m *Migrator) DownNResult(ctx context.Context, n int) (res Result, err error) {
+	if n <= 0 {
+		err = ErrorInvalidStepCount
+		return
+	}
+
+	err = m.withLock(ctx, func() (err error) {
+		res, err = m.downN(ctx, n)
+		return
+	})
+
+	return
+}
+
+func (m *Migrator) downN(ctx context.Context, n int) (res Result, err error) {
+	var plan []migration.Migration
+	db := m.session(ctx)
+
+	if res, plan, err = m.planDownN(db, n); err != nil || m.config.DryRun {
+		return
+	}
+
+	return m.rollback(ctx, db, res, plan)
+}
+
+// DownTo downgrades database revision to the target version rolling back all newer applied migrations.
+func (m *Migrator) DownTo(ctx context.Context, target int64) (oldVersion int64, newVersion int64, err error) {
+	res, err := m.DownToResult(ctx, target)
+
+	return res.OldVersion, res.NewVersion, err
+}
+
+// DownToResult downgrades database revision to the target version and returns the detailed result.
+func (m *Migrator) DownToResult(ctx context.Context, target int64) (res Result, err error) {
+	err = m.withLock(ctx, func() (err error) {
+		res, err = m.downTo(ctx, target)
+		return
+	})
+
+	return
+}
+
+func (m *Migrator) downTo(ctx context.Context, target int64) (res Result, err error) {
+	var plan []migration.Migration
+	db := m.session(ctx)
+
+	if res, plan, err = m.planDownTo(db, target); err != nil || m.config.DryRun {
+		return
+	}
+
+	return m.rollback(ctx, db, res, plan)
+}
+
+// Redo rolls back the current migration and applies it again inside a single transaction.
+func (m *Migrator) Redo(ctx context.Context) (oldVersion int64, newVersion int64, err error) {
+	err = m.withLock(ctx, func() (err error) {
+		// the version is read under the lock, so no other writer changes it before the rollback
+		if oldVersion, newVersion, err = m.version(ctx); err != nil {
+			return
+		}
+
+		if oldVersion <= 1 {
+			return ErrorNothingToRedo
+		}
+
+		redo := func(txm *Migrator) error {
+			if _, err := txm.down(ctx); err != nil {
+				return err
+			}
+
+			res, err := txm.up(ctx, oldVersion, -1)
+			if err != nil {
+				return err
+			}
+
+			newVersion = res.NewVersion
+
+			return nil
+		}
+
+		db := m.session(ctx)
+		if db == nil {
+			return redo(m)
+		}
+
+		return db.Transaction(func(tx *gorm.DB) error {
+			return redo(m.withDB(tx))
+		})
+	})
+
+	return
+}
+
+// Reset resets database to the zero-revision.
+func (m *Migrator) Reset(ctx context.Context) (oldVersion int64, newVersion int64, err error) {
+	res, err := m.ResetResult(ctx)
+
+	return res.OldVersion, res.NewVersion, err
+}
+
+// DownAll rolls back all applied migrations in reverse order, equivalent to calling `Down` repeatedly until
+// the version 1 is reached. It is an alias of `Reset`.
+func (m *Migrator) DownAll(ctx context.Context) (oldVersion int64, newVersion int64, err error) {
+	return m.Reset(ctx)
+}
+
+// ResetResult resets database to the zero-revision and returns the detailed result.
+func (m *Migrator) ResetResult(ctx context.Context) (res Result, err error) {
+	err = m.withLock(ctx, func() (err error) {
+		res, err = m.reset(ctx)
+		return
+	})
+
+	return
+}
+
+func (m *Migrator) reset(ctx context.Context) (res Result, err error) {
+	var plan []migration.Migration
+	db := m.session(ctx)
+
+	if res, plan, err = m.planReset(db); err != nil || m.config.DryRun {
+		return
+	}
+
+	return m.rollback(ctx, db, res, plan)
+}
+
+// rollback applies `down` functions of the consecutive migrations of the plan in its order.
+// The planned new version is the version which precedes the last migration of the plan.
+func (m *Migrator) rollback(ctx context.Context, db *gorm.DB, planned Result, plan []migration.Migration) (res Result, err error) {
+	res = Result{OldVersion: planned.OldVersion, NewVersion: planned.OldVersion, Skipped: planned.Skipped}
+
+	m.config.Hooks.beforeAll(DirectionDown)
+
+	for i, migr := range plan {
+		if err = m.step(ctx, db, &migr, DirectionDown, true); err != nil && !m.isAppliedDespite(db, &migr, err) {
+			break
+		}
+
+		if i+1 < len(plan) {
+			res.NewVersion = plan[i+1].Version
+		} else {
+			res.NewVersion = planned.NewVersion
+		}
+
+		res.Applied = append(res.Applied, migr)
+
+		if err != nil {
+			break
+		}
+	}
+
+	m.config.Hooks.afterAll(DirectionDown, err)
+
+	return
 }
 
-// NewMigrator returns a new instance of Migrator.
-func NewMigrator(db *gorm.DB, migrations []migration.Migration) *Migrator {
-	all := append(migrations, migration.Migration{Version: 1, Name: "-", Up: migration.DummyUpDown, Down: migration.DummyUpDown})
-	sort.Sort(migration.Migrations(all))
+// Refresh resets database to the zero-revision and then upgrades it to the latest version.
+func (m *Migrator) Refresh(ctx context.Context) (oldVersion int64, newVersion int64, err error) {
+	if oldVersion, newVersion, err = m.Reset(ctx); err != nil {
+		return
+	}
+
+	_, newVersion, err = m.Up(ctx, -1)
 
-	return &Migrator{db: db, migrations: all}
+	return
 }
 
-// Run interprets commands.
-func (m *Migrator) Run(args ...string) (oldVersion int64, newVersion int64, err error) {
-	if len(args) == 0 {
-		err = ErrorCommandRequired
+// Baseline records all migrations up to the target version (or all migrations if the target is -1) as applied
+// without calling their `up` functions. Existing history records are kept. The migrations table is initialized
+// if it does not exist.
+func (m *Migrator) Baseline(ctx context.Context, target int64) (oldVersion int64, newVersion int64, err error) {
+	if target != -1 && !m.Contains(target) {
+		err = ErrorTargetVersionNotFound
 		return
 	}
 
-	switch args[0] {
-	case "init":
-		return m.Init()
-	case "up":
-		var target int64
+	if err = m.init(ctx); err != nil {
+		return
+	}
 
-		if target, err = m.parseVersion(false, args[1:]...); err != nil {
-			return
-		}
+	err = m.withLock(ctx, func() (err error) {
+		oldVersion, newVersion, err = m.baseline(ctx, target)
+		return
+	})
 
-		return m.Up(target)
-	case "down":
-		return m.Down()
-	case "reset":
-		return m.Reset()
-	case "version":
-		return m.Version()
-	case "set_version":
-		var target int64
+	return
+}
+
+func (m *Migrator) baseline(ctx context.Context, target int64) (oldVersion int64, newVersion int64, err error) {
+	var planned Result
+	var plan []migration.Migration
+	db := m.session(ctx)
+
+	planned, plan, _, err = m.planUp(db, target, -1)
+	oldVersion = planned.OldVersion
+	newVersion = planned.NewVersion
+
+	if err != nil || m.config.DryRun || len(plan) == 0 {
+		return
+	}
+
+	store := m.historyStore(db)
 
-		if target, err = m.parseVersion(true, args[1:]...); err != nil {
+	for i := range plan {
+		plan[i].Stored = true
+		plan[i].Checksum = plan[i].ComputeChecksum()
+		m.setAppliedBy(&plan[i])
+
+		if err = store.InsertMigration(plan[i]); err != nil {
+			newVersion = oldVersion
 			return
 		}
+	}
 
-		return m.SetVersion(target)
-	default:
-		err = ErrorUnexpectedCommand
+	return
+}
+
+// Version returns current database revision version or the forced one (see `WithForcedCurrentVersion`).
+func (m *Migrator) Version(ctx context.Context) (oldVersion int64, newVersion int64, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.version(ctx)
+}
+
+// version is like `Version` but does not hold the migrator lock.
+func (m *Migrator) version(ctx context.Context) (oldVersion int64, newVersion int64, err error) {
+	if forced := m.config.ForcedCurrentVersion; forced != nil {
+		return *forced, *forced, nil
+	}
+
+	if err = m.ensureInit(ctx); err != nil {
 		return
 	}
-}
 
-// Init creates `migrations` table if it does not exist and records the initial zero-migration.
-func (m *Migrator) Init() (oldVersion int64, newVersion int64, err error) {
-	migr := &migration.Migration{Version: 1, Name: "-"}
 	var mig migration.Migration
 
-	if err = m.db.Migrator().CreateTable(&mig); err != nil {
-		// ToDo: check error details
+	if mig, err = m.historyStore(m.session(ctx)).LastMigration(); err != nil {
 		return
 	}
 
-	result := m.db.Create(&migr)
-	err = result.Error
+	oldVersion = mig.Version
+	newVersion = mig.Version
 
 	return
 }
 
-// Up upgrades database revision to the target or next version.
-func (m *Migrator) Up(target int64) (oldVersion int64, newVersion int64, err error) {
-	var history = []migration.Migration{}
-
-	if result := m.db.Order("version ASC").Find(&history); result.Error != nil {
-		err = result.Error
+// runVersion interprets `version` command and writes the description of the current migration to the output.
+func (m *Migrator) runVersion(ctx context.Context) (oldVersion int64, newVersion int64, err error) {
+	if oldVersion, newVersion, err = m.Version(ctx); err != nil || m.config.Output == nil {
 		return
 	}
 
-	for i := range history {
-		history[i].Stored = true
+	if migr, ok := m.Find(newVersion); ok {
+		fmt.Fprint(m.config.Output, migration.Describe(migr))
 	}
 
-	length := len(history)
+	return
+}
 
-	if length > 0 {
-		version := history[length-1].Version
-		oldVersion = version
-		newVersion = version
-	}
+// ClearForcedVersion removes the override of the current version set by `WithForcedCurrentVersion`.
+// Copies of the migrator are not affected.
+func (m *Migrator) ClearForcedVersion() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	merged := m.mergeMigrations(history, m.migrations, target)
+	m.config.ForcedCurrentVersion = nil
+}
 
-	for _, migr := range merged {
-		if !migr.Stored {
-			if err = migr.Up(m.db); err != nil {
-				return
-			}
+// CurrentVersion returns current database revision version.
+func (m *Migrator) CurrentVersion(ctx context.Context) (version int64, err error) {
+	_, version, err = m.Version(ctx)
+
+	return
+}
 
-			newVersion = migr.Version
-			migr.Stored = true
+// IsLatest returns `true` if current database revision version is the version of the latest migration.
+func (m *Migrator) IsLatest(ctx context.Context) (latest bool, err error) {
+	var version int64
 
-			if result := m.db.Create(&migr); result.Error != nil {
-				err = result.Error
-				return
-			}
-		}
+	if version, err = m.CurrentVersion(ctx); err != nil {
+		return
 	}
 
+	latest = version == m.migrations[len(m.migrations)-1].Version
+
 	return
 }
 
-// Down downgrades database revision to the previous version.
-func (m *Migrator) Down() (oldVersion int64, newVersion int64, err error) {
-	var old migration.Migration
+// SetVersion forces database revisiton version. The history is replaced with records of all migrations up to
+// the target version, so records of rolled back migrations are lost.
+func (m *Migrator) SetVersion(ctx context.Context, target int64) (oldVersion int64, newVersion int64, err error) {
+	err = m.withLock(ctx, func() (err error) {
+		oldVersion, newVersion, err = m.setVersion(ctx, target)
+		return
+	})
+
+	return
+}
 
-	if result := m.db.Order("version DESC").First(&old); result.Error != nil {
-		err = result.Error
+func (m *Migrator) setVersion(ctx context.Context, target int64) (oldVersion int64, newVersion int64, err error) {
+	oldVersion, _, err = m.version(ctx)
+	if err != nil {
 		return
 	}
 
-	oldVersion = old.Version
-	newVersion = old.Version
+	index := -1
+	migs := make([]migration.Migration, 0, len(m.migrations))
+
+	for i, migr := range m.migrations {
+		migr.Checksum = migr.ComputeChecksum()
+		m.setAppliedBy(&migr)
+		migs = append(migs, migr)
 
-	for i := len(m.migrations) - 1; i >= 0; i-- {
-		mig := m.migrations[i]
+		if migr.Version == target {
+			index = i
+			break
+		}
+	}
 
-		if mig.Version == old.Version {
-			if i > 0 {
-				if err = mig.Down(m.db); err != nil {
-					return
-				}
+	if index == -1 {
+		err = ErrorTargetVersionNotFound
+		return
+	} else if oldVersion == m.migrations[index].Version {
+		newVersion = oldVersion
+	}
 
-				newVersion = m.migrations[i-1].Version
+	if m.config.DryRun {
+		newVersion = migs[len(migs)-1].Version
+		return
+	}
+
+	store := m.historyStore(m.session(ctx))
+
+	if err = store.DeleteAll(); err != nil {
+		return
+	}
+
+	for _, migr := range migs {
+		if err = store.InsertMigration(migr); err != nil {
+			return
+		}
+	}
+
+	newVersion = migs[len(migs)-1].Version
+
+	return
+}
+
+// verifyChecksums compares checksums of applied migrations with checksums of actual migrations.
+// A mismatch is an error if checksum validation is enabled and a warning otherwise.
+// Migrations applied without checksums are skipped.
+func (m *Migrator) verifyChecksums(applied, actual []migration.Migration) error {
+	i := 0
+	j := 0
+
+	for i < len(applied) && j < len(actual) {
+		if applied[i].Less(&actual[j]) {
+			i++
+		} else if actual[j].Less(&applied[i]) {
+			j++
+		} else {
+			if stored, computed := applied[i].Checksum, actual[j].ComputeChecksum(); stored != "" && stored != computed {
+				err := &ChecksumMismatchError{Version: applied[i].Version, Stored: stored, Computed: computed}
 
-				if result := m.db.Delete(&mig); result.Error != nil {
-					err = result.Error
-					return
+				if m.config.ChecksumValidation {
+					return err
 				}
+
+				m.config.Logger.Info("Checksum mismatch", "version", err.Version, "stored", stored, "computed", computed)
 			}
 
-			return
+			i++
+			j++
+		}
+	}
+
+	return nil
+}
+
+// withLock calls the function while holding the migrator lock and the migrations lock. The migrations table
+// is initialized before if automatic initialization is enabled.
+func (m *Migrator) withLock(ctx context.Context, fn func() error) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err = m.ensureInit(ctx); err != nil {
+		return
+	}
+
+	db := m.session(ctx)
+
+	if err = m.config.LockStrategy.Lock(db); err != nil {
+		return &LockError{Cause: err}
+	}
+
+	defer func() {
+		if unlockErr := m.config.LockStrategy.Unlock(db); unlockErr != nil && err == nil {
+			err = unlockErr
 		}
+	}()
+
+	return fn()
+}
+
+// ensureInit initializes the migrations table once per migrator if automatic initialization is enabled.
+func (m *Migrator) ensureInit(ctx context.Context) (err error) {
+	if !m.config.AutoInit {
+		return
+	}
+
+	m.autoInit.Lock()
+	defer m.autoInit.Unlock()
+
+	if m.autoInit.done {
+		return
+	}
+
+	if err = m.initTable(ctx); err == nil {
+		m.autoInit.done = true
 	}
 
 	return
 }
 
-// Reset resets database to the zero-revision.
-func (m *Migrator) Reset() (oldVersion int64, newVersion int64, err error) {
-	history := []migration.Migration{}
+// Copy returns a copy of the migrator which uses the specified DB instance, e.g. a transaction.
+// Migrations and options are shared.
+func (m *Migrator) Copy(db *gorm.DB) *Migrator {
+	return &Migrator{db: db, store: m.historyStore(db), migrations: m.migrations, config: m.config,
+		autoInit: &autoInitState{}, skipped: &skippedState{}, mu: &sync.RWMutex{}}
+}
+
+// withDB returns a copy of the migrator which uses the specified DB instance.
+func (m *Migrator) withDB(db *gorm.DB) *Migrator {
+	return &Migrator{db: db, store: m.historyStore(db), migrations: m.migrations, config: m.config, autoInit: m.autoInit,
+		skipped: m.skipped, mu: m.mu}
+}
+
+// withDryRun returns a copy of the migrator which runs in the dry run mode.
+func (m *Migrator) withDryRun() *Migrator {
+	c := *m
+	c.config.DryRun = true
+
+	return &c
+}
+
+// session returns the DB instance bound to the context or nil if the migrator has no DB instance.
+func (m *Migrator) session(ctx context.Context) *gorm.DB {
+	if m.db == nil {
+		return nil
+	}
+
+	return m.db.WithContext(ctx)
+}
+
+// historyStore returns the history store which uses the specified DB instance if the store is a GORMStore.
+func (m *Migrator) historyStore(db *gorm.DB) MigrationStore {
+	if s, ok := m.store.(*GORMStore); ok && db != nil {
+		return s.withDB(db)
+	}
+
+	return m.store
+}
 
-	if result := m.db.Order("version ASC").Find(&history); result.Error != nil {
-		err = result.Error
+// loadHistory returns a sorted list of applied migrations.
+func (m *Migrator) loadHistory(db *gorm.DB) (history []migration.Migration, err error) {
+	if history, err = m.historyStore(db).ListMigrations(); err != nil {
 		return
 	}
 
@@ -168,104 +1077,253 @@ func (m *Migrator) Reset() (oldVersion int64, newVersion int64, err error) {
 		history[i].Stored = true
 	}
 
-	length := len(history)
+	return
+}
 
-	if length > 0 {
-		version := history[length-1].Version
-		oldVersion = version
-		newVersion = version
+// logFields returns logger fields which identify the migration. The semantic version is included
+// if semantic versioning is enabled.
+func (m *Migrator) logFields(migr *migration.Migration) []interface{} {
+	if m.config.SemanticVersioning {
+		return []interface{}{"version", migr.Version, "semantic_version", migr.Semantic(), "name", migr.Name}
 	}
 
-	var correlated []migration.Migration
+	return []interface{}{"version", migr.Version, "name", migr.Name}
+}
+
+// step applies the migration and updates the history table. Both operations are executed inside a transaction
+// if transactional migrations are enabled and the migration does not opt out. The timeout of the migration
+// limits both operations including retries.
+func (m *Migrator) step(ctx context.Context, db *gorm.DB, migr *migration.Migration, direction string, record bool) (err error) {
+	m.config.Hooks.beforeEach(*migr)
+	m.config.Logger.Info("Migration started", append(m.logFields(migr), "direction", direction)...)
+	m.writeStarted(migr, direction)
 
-	if correlated, err = m.correlateMigrations(history, m.migrations); err != nil {
-		return
-	}
+	started := time.Now()
 
-	for i := len(correlated) - 1; i >= 0; i-- {
-		migr := correlated[i]
+	err = m.intercept(ctx, db, *migr, direction, func(ctx context.Context) error {
+		if migr.Timeout > 0 {
+			var cancel context.CancelFunc
 
-		if err = migr.Down(m.db); err != nil {
-			return
+			ctx, cancel = context.WithTimeout(ctx, migr.Timeout)
+			defer cancel()
 		}
 
-		if i > 0 {
-			newVersion = correlated[i-1].Version
-		} else {
-			newVersion = migr.Version
+		if db == nil {
+			return m.applyAndRecord(ctx, db, migr, direction, record)
 		}
 
-		migr.Stored = true
+		db := db.WithContext(ctx)
 
-		// don't delete zero migration
-		if migr.Version > 1 {
-			if result := m.db.Delete(&migr); result.Error != nil {
-				err = result.Error
-				return
-			}
+		if !m.isTransactional(db, migr) {
+			return m.applyAndRecord(ctx, db, migr, direction, record)
 		}
+
+		return db.Transaction(func(tx *gorm.DB) error {
+			return m.applyAndRecord(ctx, tx, migr, direction, record)
+		})
+	})
+
+	elapsed := time.Since(started)
+
+	if err != nil {
+		m.config.Logger.Error("Migration failed", err, append(m.logFields(migr), "direction", direction,
+			"elapsed", elapsed)...)
+	} else {
+		m.config.Logger.Info("Migration completed", append(m.logFields(migr), "direction", direction,
+			"elapsed", elapsed)...)
 	}
 
+	m.writeCompleted(migr, direction, elapsed, err)
+	m.sendEvent(migr, direction, elapsed, err)
+	m.config.Hooks.afterEach(*migr, err)
+
 	return
 }
 
-// Version returns current database revision version.
-func (m *Migrator) Version() (oldVersion int64, newVersion int64, err error) {
-	var mig migration.Migration
+// isTransactional returns `true` if the migration step is executed inside its own transaction.
+func (m *Migrator) isTransactional(db *gorm.DB, migr *migration.Migration) bool {
+	return db != nil && m.config.TransactionalMigrations && !migr.NoTransaction
+}
 
-	if result := m.db.Last(&mig); result.Error != nil {
-		err = result.Error
-		return
+// isAppliedDespite returns `true` if the migration step failed but the migration stays applied and recorded:
+// the post hook failed outside of a transaction.
+func (m *Migrator) isAppliedDespite(db *gorm.DB, migr *migration.Migration, err error) bool {
+	var hookErr *PostHookError
+
+	return errors.As(err, &hookErr) && !m.isTransactional(db, migr)
+}
+
+// applyAndRecord applies the migration, inserts (up) or deletes (down) its history record if `record` is `true`
+// and calls the post hook of the migration.
+func (m *Migrator) applyAndRecord(ctx context.Context, db *gorm.DB, migr *migration.Migration, direction string, record bool) error {
+	started := time.Now()
+
+	if err := m.applyWithRetry(ctx, db, migr, direction); err != nil {
+		return err
 	}
 
-	oldVersion = mig.Version
-	newVersion = mig.Version
+	migr.ExecutionDurationMs = time.Since(started).Milliseconds()
 
-	return
+	if record {
+		if err := m.recordHistory(db, migr, direction); err != nil {
+			return err
+		}
+	}
+
+	return m.applyPostHook(db, migr, direction)
 }
 
-// SetVersion forces database revisiton version.
-func (m *Migrator) SetVersion(target int64) (oldVersion int64, newVersion int64, err error) {
-	oldVersion, _, err = m.Version()
-	if err != nil {
-		return
+// recordHistory inserts (up) or deletes (down) the history record of the applied migration.
+func (m *Migrator) recordHistory(db *gorm.DB, migr *migration.Migration, direction string) error {
+	migr.Stored = true
+
+	if direction == DirectionUp {
+		migr.Checksum = migr.ComputeChecksum()
+		migr.AppliedDirection = DirectionUp
+		m.setAppliedBy(migr)
+		// the store receives a copy, so the time is set here to be returned with the result
+		migr.AppliedAt = time.Now().UTC()
+
+		return m.historyStore(db).InsertMigration(*migr)
 	}
 
-	index := -1
-	migs := make([]migration.Migration, 0, len(m.migrations))
+	// don't delete zero migration
+	if migr.Version > 1 {
+		return m.historyStore(db).DeleteMigration(migr.Version)
+	}
 
-	for i, migr := range m.migrations {
-		migs = append(migs, migr)
+	return nil
+}
 
-		if migr.Version == target {
-			index = i
-			break
+// setAppliedBy sets the audit fields of the history record of the migration.
+func (m *Migrator) setAppliedBy(migr *migration.Migration) {
+	migr.AppliedBy = m.config.AppliedBy
+	migr.AppliedHost = m.config.AppliedHost
+}
+
+// applyPostHook calls `PostUp` or `PostDown` function of the migration and wraps an error into `PostHookError`.
+// If the function fails, the history record is rolled back with the transaction of the migration if transactional
+// migrations are enabled. Otherwise the migration stays applied (or rolled back) and recorded, and it is reported
+// by the result along with the error.
+func (m *Migrator) applyPostHook(db *gorm.DB, migr *migration.Migration, direction string) error {
+	hook := migr.PostUp
+
+	if direction == DirectionDown {
+		hook = migr.PostDown
+	}
+
+	if hook == nil {
+		return nil
+	}
+
+	if err := hook(db); err != nil {
+		return &PostHookError{Version: migr.Version, Name: migr.Name, Direction: direction, Cause: err}
+	}
+
+	return nil
+}
+
+// applyWithRetry applies the migration retrying failures according to the retry policy of the migration
+// or the default one if the migration has no policy. Interrupted migrations are not retried. Inside a transaction
+// every attempt is made in a savepoint which is rolled back on failure, so a retry starts from the same state.
+func (m *Migrator) applyWithRetry(ctx context.Context, db *gorm.DB, migr *migration.Migration, direction string) (err error) {
+	policy := migr.RetryPolicy
+
+	if policy.MaxAttempts <= 0 {
+		policy = m.config.DefaultRetryPolicy
+	}
+
+	delay := policy.BackoffBase
+	savePoint := policy.MaxAttempts > 1 && inTransaction(db)
+
+	for attempt := 1; ; attempt++ {
+		if savePoint {
+			err = m.applyInSavePoint(ctx, db, migr, direction)
+		} else {
+			err = m.apply(ctx, db, migr, direction)
+		}
+
+		if err == nil || attempt >= policy.MaxAttempts || ctx.Err() != nil {
+			return
+		}
+
+		m.config.Logger.Error("Migration attempt failed", err, append(m.logFields(migr), "direction", direction,
+			"attempt", attempt)...)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
 		}
+
+		delay *= 2
 	}
+}
 
-	if index == -1 {
-		err = ErrorTargetVersionNotFound
-		return
-	} else if oldVersion == m.migrations[index].Version {
-		newVersion = oldVersion
+// retrySavePoint is the name of the savepoint of a migration attempt inside a transaction.
+const retrySavePoint = "migrator_attempt"
+
+// inTransaction returns `true` if the DB instance is bound to a transaction.
+func inTransaction(db *gorm.DB) bool {
+	if db == nil {
+		return false
 	}
 
-	if result := m.db.Exec("DELETE FROM migrations"); result.Error != nil {
-		err = result.Error
-		return
+	committer, ok := db.Statement.ConnPool.(gorm.TxCommitter)
+
+	return ok && committer != nil && !reflect.ValueOf(committer).IsNil()
+}
+
+// applyInSavePoint is like `apply` but rolls the transaction back to the state before the attempt on failure.
+func (m *Migrator) applyInSavePoint(ctx context.Context, db *gorm.DB, migr *migration.Migration, direction string) error {
+	// a new session keeps savepoint errors away from the transaction instance
+	if err := db.Session(&gorm.Session{}).SavePoint(retrySavePoint).Error; err != nil {
+		return err
 	}
 
-	if result := m.db.Create(&migs); result.Error != nil {
-		err = result.Error
-		return
+	err := m.apply(ctx, db, migr, direction)
+	if err != nil {
+		if rollbackErr := db.Session(&gorm.Session{}).RollbackTo(retrySavePoint).Error; rollbackErr != nil {
+			return &RollbackError{Err: err, RollbackErr: rollbackErr}
+		}
 	}
 
-	newVersion = migs[len(migs)-1].Version
+	return err
+}
 
-	return
+// apply calls the migration function of the specified direction and wraps an error into `MigrationError`.
+func (m *Migrator) apply(ctx context.Context, db *gorm.DB, migr *migration.Migration, direction string) error {
+	err := ctx.Err()
+
+	if err == nil {
+		if direction == DirectionUp {
+			err = migr.ApplyUp(ctx, db)
+		} else {
+			err = migr.ApplyDown(ctx, db)
+		}
+
+		// the context error is more descriptive if the migration was interrupted
+		if err != nil && ctx.Err() != nil {
+			err = ctx.Err()
+		}
+	}
+
+	if err != nil {
+		return &MigrationError{Version: migr.Version, Name: migr.Name, Direction: direction, Cause: err}
+	}
+
+	return nil
 }
 
-func (m *Migrator) parseVersion(required bool, args ...string) (version int64, err error) {
+// parseVersion parses the version argument. If there is no argument and the environment variables prefix
+// is configured, the value of the `<prefix>_<env>` variable is used (unless `env` is empty).
+func (m *Migrator) parseVersion(required bool, env string, args ...string) (version int64, err error) {
+	if len(args) == 0 && env != "" && m.config.EnvPrefix != "" {
+		if value := os.Getenv(m.config.EnvPrefix + "_" + env); value != "" {
+			args = []string{value}
+		}
+	}
+
 	if len(args) == 0 {
 		if required {
 			err = ErrorVersionNumberRequired
@@ -306,7 +1364,7 @@ func (m *Migrator) mergeMigrations(applied, actual []migration.Migration, target
 		if applied[i].Less(&actual[j]) {
 			merged = append(merged, applied[i])
 			i++
-		} else if actual[j].Less(&applied[j]) {
+		} else if actual[j].Less(&applied[i]) {
 			merged = append(merged, actual[j])
 			j++
 		} else {
@@ -331,7 +1389,8 @@ func (m *Migrator) mergeMigrations(applied, actual []migration.Migration, target
 
 // CorrelateMigrations returns a list of correlated migrations.
 // This method replaces stored migrations with actual migrations. If some actual migration is absent
-// the method returns an error and a list which contains missing migration as the last item.
+// the method returns `AbsentMigrationError` and a list which contains missing migration as the last item.
+// The missing migration is loaded from the history and has no functions, so callers must not roll the list back.
 func (m *Migrator) correlateMigrations(applied, actual []migration.Migration) (correlated []migration.Migration, err error) {
 	appliedLength := len(applied)
 	actualLength := len(actual)
@@ -342,7 +1401,7 @@ func (m *Migrator) correlateMigrations(applied, actual []migration.Migration) (c
 	for (i < appliedLength) && (j < actualLength) {
 		if applied[i].Less(&actual[j]) {
 			correlated = append(correlated, applied[i])
-			err = ErrorSomeMigrationsAreAbsent
+			err = &AbsentMigrationError{MissingVersion: applied[i].Version}
 			return
 		} else if actual[j].Less(&applied[i]) {
 			// skip unapplied migrations
@@ -356,7 +1415,7 @@ func (m *Migrator) correlateMigrations(applied, actual []migration.Migration) (c
 
 	if i < appliedLength {
 		correlated = append(correlated, applied[i])
-		err = ErrorSomeMigrationsAreAbsent
+		err = &AbsentMigrationError{MissingVersion: applied[i].Version}
 	}
 
 	return
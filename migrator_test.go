@@ -0,0 +1,913 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// testDBCount is the number of opened test databases, it makes names of databases unique.
+var testDBCount int64
+
+// openTestDB returns a new in-memory SQLite database which is closed when the test finishes.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	name := fmt.Sprintf("%s_%d", strings.NewReplacer("/", "_", " ", "_").Replace(t.Name()), atomic.AddInt64(&testDBCount, 1))
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared&_busy_timeout=5000", name)
+
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("get database: %v", err)
+	}
+
+	// a single connection keeps the in-memory database alive and serializes access to it
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	return db
+}
+
+// tableMigration returns a migration which creates the table `t<version>` and drops it on rollback.
+func tableMigration(version int64) migration.Migration {
+	table := fmt.Sprintf("t%d", version)
+
+	return migration.Migration{
+		Version: version,
+		Name:    "create_" + table,
+		Up: func(db *gorm.DB) error {
+			return db.Exec("CREATE TABLE " + table + " (id integer)").Error
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Exec("DROP TABLE " + table).Error
+		},
+	}
+}
+
+// versionsOf returns versions of the migrations in their order.
+func versionsOf(migrations []migration.Migration) []int64 {
+	versions := make([]int64, len(migrations))
+
+	for i, migr := range migrations {
+		versions[i] = migr.Version
+	}
+
+	return versions
+}
+
+func TestMergeMigrations(t *testing.T) {
+	type entry struct {
+		version int64
+		stored  bool
+	}
+
+	applied := func(versions ...int64) []migration.Migration {
+		ms := make([]migration.Migration, len(versions))
+
+		for i, v := range versions {
+			ms[i] = migration.Migration{Version: v, Stored: true}
+		}
+
+		return ms
+	}
+	actual := func(versions ...int64) []migration.Migration {
+		ms := make([]migration.Migration, len(versions))
+
+		for i, v := range versions {
+			ms[i] = migration.Migration{Version: v}
+		}
+
+		return ms
+	}
+
+	tests := []struct {
+		name    string
+		applied []migration.Migration
+		actual  []migration.Migration
+		target  int64
+		want    []entry
+	}{
+		{
+			name:    "empty history",
+			applied: applied(),
+			actual:  actual(1, 2),
+			target:  -1,
+			want:    []entry{{1, false}, {2, false}},
+		},
+		{
+			name:    "no actual migrations",
+			applied: applied(1, 2),
+			actual:  actual(),
+			target:  -1,
+			want:    []entry{{1, true}, {2, true}},
+		},
+		{
+			name:    "applied ahead of actual",
+			applied: applied(1, 2, 3, 4),
+			actual:  actual(1, 2),
+			target:  -1,
+			want:    []entry{{1, true}, {2, true}, {3, true}, {4, true}},
+		},
+		{
+			name:    "actual ahead of applied",
+			applied: applied(1),
+			actual:  actual(1, 2, 3),
+			target:  -1,
+			want:    []entry{{1, true}, {2, false}, {3, false}},
+		},
+		{
+			name:    "interleaved",
+			applied: applied(1, 3, 5),
+			actual:  actual(1, 2, 3, 4, 5, 6),
+			target:  -1,
+			want:    []entry{{1, true}, {2, false}, {3, true}, {4, false}, {5, true}, {6, false}},
+		},
+		{
+			name:    "interleaved with unregistered applied",
+			applied: applied(1, 3, 7),
+			actual:  actual(1, 2, 4),
+			target:  -1,
+			want:    []entry{{1, true}, {2, false}, {3, true}, {4, false}, {7, true}},
+		},
+		{
+			name:    "actual cut at the target",
+			applied: applied(1, 2),
+			actual:  actual(1, 2, 3, 4),
+			target:  3,
+			want:    []entry{{1, true}, {2, true}, {3, false}},
+		},
+		{
+			name:    "applied beyond the target",
+			applied: applied(1, 4),
+			actual:  actual(1, 2, 3, 4),
+			target:  2,
+			want:    []entry{{1, true}, {2, false}, {4, true}},
+		},
+	}
+
+	m := &Migrator{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := m.mergeMigrations(tt.applied, tt.actual, tt.target)
+			got := make([]entry, len(merged))
+
+			for i, migr := range merged {
+				got[i] = entry{migr.Version, migr.Stored}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeMigrations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedoKeepsStateIfUpFails(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	migr := tableMigration(2)
+	up := migr.Up
+	calls := 0
+	migr.Up = func(db *gorm.DB) error {
+		if calls++; calls > 1 {
+			return errors.New("up failed")
+		}
+
+		return up(db)
+	}
+
+	m := NewMigrator(db, []migration.Migration{migr}, WithAutoInit(true))
+
+	if _, _, err := m.Up(ctx, -1); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	if _, _, err := m.Redo(ctx); err == nil {
+		t.Fatal("Redo() error = nil, want the up error")
+	}
+
+	// the rollback and the failed up are undone by the transaction
+	if _, version, err := m.Version(ctx); err != nil || version != 2 {
+		t.Errorf("Version() = %d, %v, want 2, nil", version, err)
+	}
+
+	if !db.Migrator().HasTable("t2") {
+		t.Error("table t2 does not exist")
+	}
+
+	var records []migration.Migration
+
+	if err := db.Table(DefaultMigrationTable).Where("version = ?", 2).Find(&records).Error; err != nil {
+		t.Fatalf("find records: %v", err)
+	}
+
+	if len(records) != 1 || records[0].AppliedDirection != DirectionUp {
+		t.Errorf("records of version 2 = %+v, want a single applied record", records)
+	}
+}
+
+func TestRedoInitialVersion(t *testing.T) {
+	m := NewMigrator(openTestDB(t), nil, WithAutoInit(true))
+
+	if _, _, err := m.Redo(context.Background()); !errors.Is(err, ErrorNothingToRedo) {
+		t.Errorf("Redo() error = %v, want %v", err, ErrorNothingToRedo)
+	}
+}
+
+func TestNilApplyFunctions(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	upOnly := tableMigration(3)
+	upOnly.Down = nil
+
+	migrations := []migration.Migration{
+		tableMigration(2),
+		upOnly,
+		{Version: 4, Name: "placeholder"},
+		{Version: 5, Name: "down_only", Down: func(db *gorm.DB) error { return nil }},
+	}
+	m := NewMigrator(db, migrations, WithAutoInit(true))
+
+	if _, version, err := m.Up(ctx, -1); err != nil || version != 5 {
+		t.Fatalf("Up() = %d, %v, want 5, nil", version, err)
+	}
+
+	if _, version, err := m.Reset(ctx); err != nil || version != 1 {
+		t.Fatalf("Reset() = %d, %v, want 1, nil", version, err)
+	}
+
+	// the nil `down` function of the version 3 leaves its table
+	if db.Migrator().HasTable("t2") || !db.Migrator().HasTable("t3") {
+		t.Errorf("tables t2, t3 exist = %t, %t, want false, true", db.Migrator().HasTable("t2"), db.Migrator().HasTable("t3"))
+	}
+}
+
+// countRecords returns the number of rows in the migrations table.
+func countRecords(t *testing.T, db *gorm.DB, table string) (count int64) {
+	t.Helper()
+
+	if err := db.Table(table).Count(&count).Error; err != nil {
+		t.Fatalf("count records: %v", err)
+	}
+
+	return
+}
+
+func TestAutoInitConcurrentUp(t *testing.T) {
+	const goroutines = 2
+
+	ctx := context.Background()
+	db := openTestDB(t)
+	m := NewMigrator(db, []migration.Migration{tableMigration(2)}, WithAutoInit(true))
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, goroutines)
+
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if _, _, err := m.Up(ctx, -1); err != nil {
+				errs <- err
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("Up() error = %v", err)
+	}
+
+	var initial int64
+
+	if err := db.Table(DefaultMigrationTable).Where("version = ?", 1).Count(&initial).Error; err != nil {
+		t.Fatalf("count records: %v", err)
+	}
+
+	if initial != 1 {
+		t.Errorf("initial records = %d, want 1", initial)
+	}
+
+	if count := countRecords(t, db, DefaultMigrationTable); count != 2 {
+		t.Errorf("records = %d, want 2", count)
+	}
+}
+
+func TestConcurrentUp(t *testing.T) {
+	const goroutines = 10
+
+	ctx := context.Background()
+	db := openTestDB(t)
+	migrations := []migration.Migration{tableMigration(2), tableMigration(3), tableMigration(4)}
+	m := NewMigrator(db, migrations)
+
+	if _, _, err := m.Init(ctx); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, 2*goroutines)
+
+	for i := 0; i < goroutines; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			if _, _, err := m.Up(ctx, -1); err != nil {
+				errs <- err
+			}
+		}()
+
+		// readers run alongside writers
+		go func() {
+			defer wg.Done()
+
+			if _, err := m.Status(ctx); err != nil {
+				errs <- err
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("concurrent call error = %v", err)
+	}
+
+	if count := countRecords(t, db, DefaultMigrationTable); count != int64(len(migrations)+1) {
+		t.Errorf("records = %d, want %d", count, len(migrations)+1)
+	}
+
+	if _, version, err := m.Version(ctx); err != nil || version != 4 {
+		t.Errorf("Version() = %d, %v, want 4, nil", version, err)
+	}
+}
+
+func TestCorrelateMigrationsAppliedAhead(t *testing.T) {
+	m := &Migrator{}
+	applied := []migration.Migration{{Version: 1, Stored: true}, {Version: 2, Stored: true}, {Version: 3, Stored: true}}
+	actual := []migration.Migration{tableMigration(1), tableMigration(2)}
+
+	correlated, err := m.correlateMigrations(applied, actual)
+
+	var absentErr *AbsentMigrationError
+
+	if !errors.As(err, &absentErr) || absentErr.MissingVersion != 3 {
+		t.Fatalf("correlateMigrations() error = %v, want an absent migration error of the version 3", err)
+	}
+
+	if got := versionsOf(correlated); !reflect.DeepEqual(got, []int64{1, 2, 3}) {
+		t.Errorf("correlateMigrations() versions = %v, want [1 2 3]", got)
+	}
+}
+
+func TestRollbackWithAbsentMigrations(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	newer := NewMigrator(db, []migration.Migration{tableMigration(2), tableMigration(3)}, WithAutoInit(true))
+
+	if _, _, err := newer.Up(ctx, -1); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	// an older release does not know the version 3
+	older := NewMigrator(db, []migration.Migration{tableMigration(2)})
+
+	// the current migration is unknown, so there is nothing to roll back
+	if oldVersion, newVersion, err := older.Down(ctx); err != nil || oldVersion != 3 || newVersion != 3 {
+		t.Errorf("Down() = %d, %d, %v, want 3, 3, nil", oldVersion, newVersion, err)
+	}
+
+	var absentErr *AbsentMigrationError
+
+	if _, _, err := older.Reset(ctx); !errors.As(err, &absentErr) || absentErr.MissingVersion != 3 {
+		t.Fatalf("Reset() error = %v, want an absent migration error of the version 3", err)
+	}
+
+	if _, version, err := older.Version(ctx); err != nil || version != 3 {
+		t.Errorf("Version() = %d, %v, want 3, nil", version, err)
+	}
+
+	if !db.Migrator().HasTable("t2") || !db.Migrator().HasTable("t3") {
+		t.Error("tables are rolled back")
+	}
+}
+
+func TestContinueOnErrorSkipsDependents(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	errUp := errors.New("up failed")
+	failing := tableMigration(2)
+	failing.Up = func(db *gorm.DB) error { return errUp }
+
+	child := tableMigration(3)
+	child.DependsOn = []int64{2}
+
+	grandchild := tableMigration(4)
+	grandchild.DependsOn = []int64{3}
+
+	m := NewMigrator(db, []migration.Migration{failing, child, grandchild, tableMigration(5)},
+		WithAutoInit(true), WithErrorStrategy(ContinueOnError))
+
+	res, err := m.UpResult(ctx, -1)
+
+	var multiErr *MultiError
+
+	if !errors.As(err, &multiErr) {
+		t.Fatalf("UpResult() error = %v, want %T", err, multiErr)
+	}
+
+	errs := multiErr.Errors()
+	if len(errs) != 3 || !errors.Is(errs[0], errUp) {
+		t.Fatalf("errors = %v, want the up error and two dependency errors", errs)
+	}
+
+	for i, want := range []DependencyError{{Version: 3, DependsOn: 2}, {Version: 4, DependsOn: 3}} {
+		var depErr *DependencyError
+
+		if !errors.As(errs[i+1], &depErr) || *depErr != want {
+			t.Errorf("errors[%d] = %v, want %v", i+1, errs[i+1], &want)
+		}
+	}
+
+	if got := versionsOf(res.Applied); !reflect.DeepEqual(got, []int64{5}) {
+		t.Errorf("applied versions = %v, want [5]", got)
+	}
+
+	for _, table := range []string{"t3", "t4"} {
+		if db.Migrator().HasTable(table) {
+			t.Errorf("table %s of a dependent migration exists", table)
+		}
+	}
+}
+
+func TestRetryInsideTransaction(t *testing.T) {
+	db := openTestDB(t)
+	attempts := 0
+
+	// the first attempt creates the table and fails, so the retry must start from the state before it
+	flaky := tableMigration(2)
+	up := flaky.Up
+	flaky.Up = func(db *gorm.DB) error {
+		if err := up(db); err != nil {
+			return err
+		}
+
+		if attempts++; attempts == 1 {
+			return errors.New("temporary failure")
+		}
+
+		return nil
+	}
+	flaky.RetryPolicy = migration.RetryPolicy{MaxAttempts: 3}
+
+	m := NewMigrator(db, []migration.Migration{flaky}, WithAutoInit(true), WithTransactionalMigrations(true))
+
+	if _, version, err := m.Up(context.Background(), -1); err != nil || version != 2 {
+		t.Fatalf("Up() = %d, %v, want 2, nil", version, err)
+	}
+
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+
+	if !db.Migrator().HasTable("t2") {
+		t.Error("table t2 does not exist")
+	}
+}
+
+func TestConcurrentRedo(t *testing.T) {
+	const goroutines = 5
+
+	ctx := context.Background()
+	db := openTestDB(t)
+	m := NewMigrator(db, []migration.Migration{tableMigration(2), tableMigration(3)}, WithAutoInit(true))
+
+	if _, _, err := m.Up(ctx, -1); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, goroutines)
+
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			// every redo observes the version left by the previous one
+			if oldVersion, newVersion, err := m.Redo(ctx); err != nil {
+				errs <- err
+			} else if oldVersion != 3 || newVersion != 3 {
+				errs <- fmt.Errorf("Redo() = %d, %d, want 3, 3", oldVersion, newVersion)
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if _, version, err := m.Version(ctx); err != nil || version != 3 {
+		t.Errorf("Version() = %d, %v, want 3, nil", version, err)
+	}
+}
+
+func TestGroupedMigrations(t *testing.T) {
+	errUp := errors.New("up failed")
+
+	newMigrations := func() []migration.Migration {
+		first := tableMigration(2)
+		first.Group = "users"
+
+		// the second member creates its table and fails
+		second := tableMigration(3)
+		second.Group = "users"
+		up := second.Up
+		second.Up = func(db *gorm.DB) error {
+			if err := up(db); err != nil {
+				return err
+			}
+
+			return errUp
+		}
+
+		return []migration.Migration{first, second, tableMigration(4)}
+	}
+
+	tests := []struct {
+		name    string
+		grouped bool
+		version int64
+		tables  []string
+	}{
+		{"grouped", true, 1, nil},
+		{"not grouped", false, 2, []string{"t2", "t3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			db := openTestDB(t)
+			m := NewMigrator(db, newMigrations(), WithAutoInit(true), WithGroupedMigrations(tt.grouped))
+
+			res, err := m.UpResult(ctx, -1)
+			if !errors.Is(err, errUp) {
+				t.Fatalf("UpResult() error = %v, want %v", err, errUp)
+			}
+
+			if res.NewVersion != tt.version {
+				t.Errorf("new version = %d, want %d", res.NewVersion, tt.version)
+			}
+
+			if _, version, _ := m.Version(ctx); version != tt.version {
+				t.Errorf("Version() = %d, want %d", version, tt.version)
+			}
+
+			for _, table := range []string{"t2", "t3", "t4"} {
+				want := false
+
+				for _, name := range tt.tables {
+					want = want || name == table
+				}
+
+				if got := db.Migrator().HasTable(table); got != want {
+					t.Errorf("table %s exists = %v, want %v", table, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGroupedMigrationsApplied(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	migrations := []migration.Migration{tableMigration(2), tableMigration(3), tableMigration(4)}
+	migrations[0].Group = "users"
+	migrations[1].Group = "users"
+	migrations[1].NoTransaction = true
+
+	m := NewMigrator(db, migrations, WithAutoInit(true), WithGroupedMigrations(true),
+		WithTransactionalMigrations(true))
+
+	res, err := m.UpResult(ctx, -1)
+	if err != nil || res.NewVersion != 4 {
+		t.Fatalf("UpResult() = %d, %v, want 4, nil", res.NewVersion, err)
+	}
+
+	if got := versionsOf(res.Applied); !reflect.DeepEqual(got, []int64{2, 3, 4}) {
+		t.Errorf("applied versions = %v, want [2 3 4]", got)
+	}
+}
+
+func TestGroupLength(t *testing.T) {
+	grouped := func(version int64, group string) migration.Migration {
+		migr := tableMigration(version)
+		migr.Group = group
+
+		return migr
+	}
+
+	plan := []migration.Migration{
+		grouped(2, "a"), grouped(3, "a"), grouped(4, ""), grouped(5, "a"), grouped(6, "b"), grouped(7, "b"),
+	}
+
+	db := openTestDB(t)
+	m := NewMigrator(db, nil, WithGroupedMigrations(true))
+
+	tests := []struct {
+		start int
+		db    *gorm.DB
+		want  int
+	}{
+		{0, db, 2},
+		{1, db, 1},
+		{2, db, 1},
+		{3, db, 1},
+		{4, db, 2},
+		{0, nil, 1},
+	}
+
+	for _, tt := range tests {
+		if got := m.groupLength(tt.db, plan[tt.start:]); got != tt.want {
+			t.Errorf("groupLength(plan[%d:], db: %v) = %d, want %d", tt.start, tt.db != nil, got, tt.want)
+		}
+	}
+
+	if got := NewMigrator(db, nil).groupLength(db, plan); got != 1 {
+		t.Errorf("groupLength() without grouping = %d, want 1", got)
+	}
+}
+
+func TestPostHooks(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	migr := tableMigration(2)
+	migr.PostUp = func(db *gorm.DB) error { return db.Exec("INSERT INTO t2 (id) VALUES (1)").Error }
+	migr.PostDown = func(db *gorm.DB) error { return db.Exec("CREATE TABLE t2_dropped (id INTEGER)").Error }
+
+	m := NewMigrator(db, []migration.Migration{migr}, WithAutoInit(true))
+
+	if _, _, err := m.Up(ctx, -1); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	if got := countRecords(t, db, "t2"); got != 1 {
+		t.Errorf("t2 count = %d, want 1", got)
+	}
+
+	if _, _, err := m.Down(ctx); err != nil {
+		t.Fatalf("Down() error = %v", err)
+	}
+
+	if !db.Migrator().HasTable("t2_dropped") {
+		t.Error("PostDown is not called")
+	}
+}
+
+func TestPostHookFailure(t *testing.T) {
+	errHook := errors.New("hook failed")
+
+	tests := []struct {
+		name          string
+		transactional bool
+		upVersion     int64
+		downVersion   int64
+	}{
+		// the migration stays applied and recorded, so the results report it
+		{"without transaction", false, 2, 1},
+		// the transaction of the migration is rolled back with its history record
+		{"inside transaction", true, 1, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			db := openTestDB(t)
+
+			failing := tableMigration(2)
+			failing.PostUp = func(db *gorm.DB) error { return errHook }
+
+			m := NewMigrator(db, []migration.Migration{failing, tableMigration(3)}, WithAutoInit(true),
+				WithTransactionalMigrations(tt.transactional))
+
+			res, err := m.UpResult(ctx, -1)
+
+			var hookErr *PostHookError
+
+			if !errors.As(err, &hookErr) || !errors.Is(err, errHook) ||
+				hookErr.Version != 2 || hookErr.Direction != DirectionUp {
+				t.Fatalf("UpResult() error = %v, want a PostHookError of 2 up", err)
+			}
+
+			if res.NewVersion != tt.upVersion || len(res.Applied) != int(tt.upVersion-1) {
+				t.Errorf("UpResult() = %d, %v, want %d", res.NewVersion, versionsOf(res.Applied), tt.upVersion)
+			}
+
+			if _, version, _ := m.Version(ctx); version != tt.upVersion {
+				t.Errorf("Version() after Up = %d, want %d", version, tt.upVersion)
+			}
+
+			if got := db.Migrator().HasTable("t2"); got != !tt.transactional {
+				t.Errorf("table t2 exists = %v, want %v", got, !tt.transactional)
+			}
+
+			if db.Migrator().HasTable("t3") {
+				t.Error("the migration after the failed one is applied")
+			}
+
+			// the rollback hook fails the same way
+			failing.PostUp = nil
+			failing.PostDown = func(db *gorm.DB) error { return errHook }
+
+			db = openTestDB(t)
+			m = NewMigrator(db, []migration.Migration{failing}, WithAutoInit(true),
+				WithTransactionalMigrations(tt.transactional))
+
+			if _, _, err = m.Up(ctx, -1); err != nil {
+				t.Fatalf("Up() error = %v", err)
+			}
+
+			if res, err = m.DownResult(ctx); !errors.As(err, &hookErr) || hookErr.Direction != DirectionDown {
+				t.Fatalf("DownResult() error = %v, want a PostHookError of 2 down", err)
+			}
+
+			if res.NewVersion != tt.downVersion {
+				t.Errorf("DownResult() new version = %d, want %d", res.NewVersion, tt.downVersion)
+			}
+
+			if _, version, _ := m.Version(ctx); version != tt.downVersion {
+				t.Errorf("Version() after Down = %d, want %d", version, tt.downVersion)
+			}
+		})
+	}
+}
+
+func TestRollbackOnError(t *testing.T) {
+	errUp := errors.New("up failed")
+	errDown := errors.New("down failed")
+
+	tests := []struct {
+		name        string
+		enabled     bool
+		failingDown bool
+		version     int64
+		applied     []int64
+	}{
+		{"disabled", false, false, 4, []int64{3, 4}},
+		{"enabled", true, false, 2, []int64{}},
+		{"rollback fails", true, true, 4, []int64{3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			db := openTestDB(t)
+
+			rollback := tableMigration(4)
+			if tt.failingDown {
+				rollback.Down = func(db *gorm.DB) error { return errDown }
+			}
+
+			failing := tableMigration(5)
+			failing.Up = func(db *gorm.DB) error { return errUp }
+
+			m := NewMigrator(db, []migration.Migration{tableMigration(2), tableMigration(3), rollback, failing},
+				WithAutoInit(true), WithRollbackOnError(tt.enabled))
+
+			// the migration applied by the previous call is kept
+			if _, _, err := m.Up(ctx, 2); err != nil {
+				t.Fatalf("Up(2) error = %v", err)
+			}
+
+			res, err := m.UpResult(ctx, -1)
+			if !errors.Is(err, errUp) {
+				t.Fatalf("UpResult() error = %v, want %v", err, errUp)
+			}
+
+			var rollbackErr *RollbackError
+
+			if got := errors.As(err, &rollbackErr); got != tt.failingDown {
+				t.Errorf("UpResult() error = %v, RollbackError = %v, want %v", err, got, tt.failingDown)
+			} else if got && !errors.Is(rollbackErr.RollbackErr, errDown) {
+				t.Errorf("rollback error = %v, want %v", rollbackErr.RollbackErr, errDown)
+			}
+
+			if got := versionsOf(res.Applied); res.NewVersion != tt.version || !reflect.DeepEqual(got, tt.applied) {
+				t.Errorf("UpResult() = %d, %v, want %d, %v", res.NewVersion, got, tt.version, tt.applied)
+			}
+
+			if _, version, _ := m.Version(ctx); version != tt.version {
+				t.Errorf("Version() = %d, want %d", version, tt.version)
+			}
+
+			if !db.Migrator().HasTable("t2") {
+				t.Error("table t2 of the previous call is rolled back")
+			}
+
+			if got := db.Migrator().HasTable("t3"); got != (tt.version > 2) {
+				t.Errorf("table t3 exists = %v, want %v", got, tt.version > 2)
+			}
+		})
+	}
+}
+
+// uncheckedStore hides optional interfaces of the wrapped store, e.g. the table existence check.
+type uncheckedStore struct {
+	MigrationStore
+}
+
+func TestInitIfNotExists(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		store func(db *gorm.DB) MigrationStore
+	}{
+		{"checked store", func(db *gorm.DB) MigrationStore { return NewGORMStore(db, DefaultMigrationTable) }},
+		{"unchecked store", func(db *gorm.DB) MigrationStore {
+			return uncheckedStore{NewGORMStore(db, DefaultMigrationTable)}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			m := NewMigratorWithStore(tt.store(db), []migration.Migration{tableMigration(2)}, WithMigrationDB(db))
+
+			oldVersion, newVersion, initialized, err := m.InitIfNotExists(ctx)
+			if err != nil || oldVersion != 0 || newVersion != 1 || !initialized {
+				t.Fatalf("first InitIfNotExists() = %d, %d, %v, %v, want 0, 1, true, nil",
+					oldVersion, newVersion, initialized, err)
+			}
+
+			if _, newVersion, initialized, err = m.InitIfNotExists(ctx); err != nil || newVersion != 1 || initialized {
+				t.Fatalf("second InitIfNotExists() = %d, %v, %v, want 1, false, nil", newVersion, initialized, err)
+			}
+
+			if _, _, err = m.Up(ctx, -1); err != nil {
+				t.Fatalf("Up() error = %v", err)
+			}
+
+			if _, newVersion, initialized, err = m.InitIfNotExists(ctx); err != nil || newVersion != 2 || initialized {
+				t.Errorf("InitIfNotExists() after Up = %d, %v, %v, want 2, false, nil", newVersion, initialized, err)
+			}
+		})
+	}
+}
+
+func TestInitIfNotExistsWithoutInitialRecord(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	// the table is created by other means, so the initial record is missing
+	if err := NewGORMStore(db, DefaultMigrationTable).CreateTable(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewMigrator(db, []migration.Migration{tableMigration(2)})
+
+	_, newVersion, initialized, err := m.InitIfNotExists(ctx)
+	if err != nil || newVersion != 1 || initialized {
+		t.Fatalf("InitIfNotExists() = %d, %v, %v, want 1, false, nil", newVersion, initialized, err)
+	}
+
+	if got := countRecords(t, db, DefaultMigrationTable); got != 1 {
+		t.Errorf("history records = %d, want 1", got)
+	}
+}
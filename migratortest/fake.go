@@ -0,0 +1,105 @@
+package migratortest
+
+import (
+	"context"
+	"sync"
+
+	migrator "github.com/Devoter/gorm-migrator"
+)
+
+// Call declares a recorded call of a FakeMigrator method.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Return declares values returned by a FakeMigrator method.
+type Return struct {
+	OldVersion int64
+	NewVersion int64
+	Err        error
+}
+
+// FakeMigrator is a MigratorInterface implementation which records calls and returns configured values.
+type FakeMigrator struct {
+	mu      sync.Mutex
+	returns map[string]Return
+	calls   []Call
+}
+
+var _ migrator.MigratorInterface = (*FakeMigrator)(nil)
+
+// NewFakeMigrator returns a new instance of FakeMigrator.
+func NewFakeMigrator() *FakeMigrator {
+	return &FakeMigrator{returns: map[string]Return{}}
+}
+
+// SetReturn sets values returned by the method with the specified name (e.g. "Up").
+func (f *FakeMigrator) SetReturn(method string, oldVersion int64, newVersion int64, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.returns[method] = Return{OldVersion: oldVersion, NewVersion: newVersion, Err: err}
+}
+
+// Calls returns a list of recorded calls.
+func (f *FakeMigrator) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	calls := make([]Call, len(f.calls))
+	copy(calls, f.calls)
+
+	return calls
+}
+
+// Init records the call and returns configured values.
+func (f *FakeMigrator) Init(ctx context.Context) (int64, int64, error) {
+	return f.call("Init")
+}
+
+// Up records the call and returns configured values.
+func (f *FakeMigrator) Up(ctx context.Context, target int64) (int64, int64, error) {
+	return f.call("Up", target)
+}
+
+// Down records the call and returns configured values.
+func (f *FakeMigrator) Down(ctx context.Context) (int64, int64, error) {
+	return f.call("Down")
+}
+
+// Reset records the call and returns configured values.
+func (f *FakeMigrator) Reset(ctx context.Context) (int64, int64, error) {
+	return f.call("Reset")
+}
+
+// Version records the call and returns configured values.
+func (f *FakeMigrator) Version(ctx context.Context) (int64, int64, error) {
+	return f.call("Version")
+}
+
+// SetVersion records the call and returns configured values.
+func (f *FakeMigrator) SetVersion(ctx context.Context, target int64) (int64, int64, error) {
+	return f.call("SetVersion", target)
+}
+
+// Run records the call and returns configured values.
+func (f *FakeMigrator) Run(ctx context.Context, args ...string) (int64, int64, error) {
+	callArgs := make([]interface{}, len(args))
+
+	for i, arg := range args {
+		callArgs[i] = arg
+	}
+
+	return f.call("Run", callArgs...)
+}
+
+func (f *FakeMigrator) call(method string, args ...interface{}) (int64, int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, Call{Method: method, Args: args})
+	ret := f.returns[method]
+
+	return ret.OldVersion, ret.NewVersion, ret.Err
+}
@@ -0,0 +1,145 @@
+package migratortest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	migrator "github.com/Devoter/gorm-migrator"
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// DefaultDSN is the default data source name of test databases: an in-memory SQLite database.
+const DefaultDSN = "file::memory:"
+
+// StateChecker declares a func type which checks the database state and returns an error if it is unexpected.
+type StateChecker func(db *gorm.DB) error
+
+// TestConfig declares settings of migration tests.
+type TestConfig struct {
+	// DSN is the data source name of the test database, `DefaultDSN` is used if it is empty.
+	DSN string
+	// Open returns a dialector of the DSN, SQLite is used if it is nil.
+	Open func(dsn string) gorm.Dialector
+	// Migrations is a list of tested migrations.
+	Migrations []migration.Migration
+	// Options are options of the tested migrator.
+	Options []migrator.MigratorOption
+}
+
+// TestCase declares a migration test case. Every case uses a new database.
+type TestCase struct {
+	Name string
+	// Setup prepares the initial database state.
+	Setup func(db *gorm.DB) error
+	// Commands are arguments of `Run` calls, e.g. `{"init"}, {"up"}`.
+	Commands [][]string
+	// OldVersion and NewVersion are expected versions returned by the last command.
+	OldVersion int64
+	NewVersion int64
+	// Check verifies the database state after the commands.
+	Check StateChecker
+	// Err is an expected error of the commands which is compared using `errors.Is`. Commands after the failed one
+	// are not run.
+	Err error
+}
+
+// RunMigrationTest runs every test case as a subtest.
+func RunMigrationTest(t *testing.T, cfg TestConfig, cases []TestCase) {
+	t.Helper()
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.Name, func(t *testing.T) {
+			runCase(t, cfg, tc)
+		})
+	}
+}
+
+// runCase runs the test case on a new database.
+func runCase(t *testing.T, cfg TestConfig, tc TestCase) {
+	t.Helper()
+
+	db := openDB(t, cfg)
+
+	if tc.Setup != nil {
+		if err := tc.Setup(db); err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+	}
+
+	m := migrator.NewMigrator(db, cfg.Migrations, cfg.Options...)
+	ctx := context.Background()
+
+	var (
+		oldVersion int64
+		newVersion int64
+		err        error
+	)
+
+	for _, args := range tc.Commands {
+		if oldVersion, newVersion, err = m.Run(ctx, args...); err != nil {
+			break
+		}
+	}
+
+	if tc.Err != nil {
+		if !errors.Is(err, tc.Err) {
+			t.Fatalf("unexpected error: got %v, want %v", err, tc.Err)
+		}
+	} else if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if oldVersion != tc.OldVersion || newVersion != tc.NewVersion {
+		t.Errorf("unexpected versions: got %d -> %d, want %d -> %d", oldVersion, newVersion, tc.OldVersion,
+			tc.NewVersion)
+	}
+
+	if tc.Check != nil {
+		if err := tc.Check(db); err != nil {
+			t.Errorf("unexpected database state: %v", err)
+		}
+	}
+}
+
+// openDB opens a new test database which is closed when the test finishes.
+func openDB(t *testing.T, cfg TestConfig) *gorm.DB {
+	t.Helper()
+
+	dsn := cfg.DSN
+
+	if dsn == "" {
+		dsn = DefaultDSN
+	}
+
+	open := cfg.Open
+
+	if open == nil {
+		open = sqlite.Open
+	}
+
+	db, err := gorm.Open(open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+
+	// every connection to an in-memory SQLite database has its own database
+	sqlDB.SetMaxOpenConns(1)
+
+	t.Cleanup(func() {
+		_ = sqlDB.Close()
+	})
+
+	return db
+}
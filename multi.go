@@ -0,0 +1,113 @@
+package migrator
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// MultiMigrator declares a manager which applies the same migrations to several databases.
+type MultiMigrator struct {
+	migrators map[string]*Migrator
+}
+
+// MultiMigratorError declares errors of MultiMigrator operations keyed by target names.
+type MultiMigratorError map[string]error
+
+func (e MultiMigratorError) Error() string {
+	names := make([]string, 0, len(e))
+
+	for name := range e {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	messages := make([]string, len(names))
+
+	for i, name := range names {
+		messages[i] = name + ": " + e[name].Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// NewMultiMigrator returns a new instance of MultiMigrator with a migrator per target database.
+func NewMultiMigrator(targets map[string]*gorm.DB, migrations []migration.Migration, opts ...MigratorOption) *MultiMigrator {
+	migrators := make(map[string]*Migrator, len(targets))
+
+	for name, db := range targets {
+		migrs := make([]migration.Migration, len(migrations))
+		copy(migrs, migrations)
+		migrators[name] = NewMigrator(db, migrs, opts...)
+	}
+
+	return &MultiMigrator{migrators: migrators}
+}
+
+// Migrator returns the migrator of the target or nil if there is no such target.
+func (mm *MultiMigrator) Migrator(name string) *Migrator {
+	return mm.migrators[name]
+}
+
+// UpAll upgrades all target databases to the latest version.
+func (mm *MultiMigrator) UpAll(ctx context.Context) (map[string]Result, error) {
+	return mm.each(func(m *Migrator) (Result, error) {
+		return m.UpResult(ctx, -1)
+	})
+}
+
+// DownAll downgrades all target databases to the previous version.
+func (mm *MultiMigrator) DownAll(ctx context.Context) (map[string]Result, error) {
+	return mm.each(func(m *Migrator) (Result, error) {
+		return m.DownResult(ctx)
+	})
+}
+
+// ResetAll resets all target databases to the zero-revision.
+func (mm *MultiMigrator) ResetAll(ctx context.Context) (map[string]Result, error) {
+	return mm.each(func(m *Migrator) (Result, error) {
+		return m.ResetResult(ctx)
+	})
+}
+
+// StatusAll returns states of migrations of all target databases.
+func (mm *MultiMigrator) StatusAll(ctx context.Context) (statuses map[string][]MigrationStatus, err error) {
+	statuses = make(map[string][]MigrationStatus, len(mm.migrators))
+	errs := MultiMigratorError{}
+
+	for name, m := range mm.migrators {
+		if statuses[name], err = m.Status(ctx); err != nil {
+			errs[name] = err
+		}
+	}
+
+	return statuses, errs.orNil()
+}
+
+// each calls the function for every target. A failure does not stop the execution against remaining targets.
+func (mm *MultiMigrator) each(fn func(m *Migrator) (Result, error)) (results map[string]Result, err error) {
+	results = make(map[string]Result, len(mm.migrators))
+	errs := MultiMigratorError{}
+
+	for name, m := range mm.migrators {
+		if results[name], err = fn(m); err != nil {
+			errs[name] = err
+		}
+	}
+
+	return results, errs.orNil()
+}
+
+// orNil returns nil if there are no errors.
+func (e MultiMigratorError) orNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
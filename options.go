@@ -0,0 +1,362 @@
+package migrator
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Devoter/gorm-migrator/lock"
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// DefaultMigrationTable is the default name of the migrations history table.
+const DefaultMigrationTable = "migrations"
+
+// SystemAppliedBy is the `AppliedBy` value of the history record of the initial zero-migration.
+const SystemAppliedBy = "system"
+
+// Config declares migrator settings.
+type Config struct {
+	Schema                  string
+	TableName               string
+	Logger                  Logger
+	LockStrategy            LockStrategy
+	Hooks                   Hooks
+	TransactionalMigrations bool
+	DryRun                  bool
+	MaxVersionGap           int64
+	ChecksumValidation      bool
+	Output                  io.Writer
+	JSONOutput              io.Writer
+	DefaultTags             []string
+	VersionValidator        VersionValidator
+	Interceptors            []Interceptor
+	ErrorStrategy           ErrorStrategy
+	DefaultRetryPolicy      migration.RetryPolicy
+	AutoInit                bool
+	MaxNameLength           int
+	StrictNoopCheck         bool
+	EnvPrefix               string
+	WaitForDBInterval       time.Duration
+	RollbackOnError         bool
+	EventChannel            chan<- MigrationEvent
+	AutoMigrate             bool
+	WarnOnMissingDown       func(version int64, name string)
+	SkipVersions            []int64
+	SemanticVersioning      bool
+	DialectInit             func(db *gorm.DB) error
+	ForcedCurrentVersion    *int64
+	GroupedMigrations       bool
+	AppliedBy               string
+	AppliedHost             string
+	MigrationDB             *gorm.DB
+}
+
+// ErrorStrategy declares a behavior of `Up` on a migration failure.
+type ErrorStrategy int
+
+const (
+	// StopOnError means that `Up` returns the error of the first failed migration. It is the default strategy.
+	StopOnError ErrorStrategy = iota
+	// ContinueOnError means that `Up` attempts all pending migrations and returns errors as `MultiError`.
+	ContinueOnError
+)
+
+// DefaultRetryPolicy is the retry policy of new migrators which is used for migrations without own policy.
+var DefaultRetryPolicy = migration.RetryPolicy{}
+
+// MigratorOption declares a func type which modifies migrator settings.
+type MigratorOption func(c *Config)
+
+// WithMigrationTable sets the name of the migrations history table.
+func WithMigrationTable(name string) MigratorOption {
+	return func(c *Config) {
+		c.TableName = name
+	}
+}
+
+// WithTableName is an alias of `WithMigrationTable`. The name is passed to `db.Table` by every query,
+// so GORM `NamingStrategy` of the application (e.g. prefixes or pluralization) is never applied to it.
+// The same is true for the default name `DefaultMigrationTable`.
+func WithTableName(name string) MigratorOption {
+	return WithMigrationTable(name)
+}
+
+// WithSchema sets the schema of the migrations history table (e.g. PostgreSQL schema).
+func WithSchema(schema string) MigratorOption {
+	return func(c *Config) {
+		c.Schema = schema
+	}
+}
+
+// WithLogger sets the migrator logger.
+func WithLogger(l Logger) MigratorOption {
+	return func(c *Config) {
+		c.Logger = l
+	}
+}
+
+// WithLockStrategy sets the locking strategy which prevents concurrent migration runs.
+func WithLockStrategy(ls LockStrategy) MigratorOption {
+	return func(c *Config) {
+		c.LockStrategy = ls
+	}
+}
+
+// WithHooks sets migration hooks.
+func WithHooks(h Hooks) MigratorOption {
+	return func(c *Config) {
+		c.Hooks = h
+	}
+}
+
+// WithTransactionalMigrations enables or disables running of each migration and its history record update
+// inside a single transaction. Migrations which can not be executed inside a transaction (e.g. MySQL DDL statements
+// which cause an implicit commit) should set `NoTransaction` field.
+func WithTransactionalMigrations(enabled bool) MigratorOption {
+	return func(c *Config) {
+		c.TransactionalMigrations = enabled
+	}
+}
+
+// WithDryRun enables or disables the dry run mode. In this mode `Up`, `Down`, `Reset`, `Baseline`, `SetVersion`
+// and `Repair` only compute the resulting versions and do not change the history or the schema.
+func WithDryRun(enabled bool) MigratorOption {
+	return func(c *Config) {
+		c.DryRun = enabled
+	}
+}
+
+// WithMaxVersionGap sets the maximum gap between consecutive migration versions. Larger gaps are reported
+// to the logger by `Validate`. Zero value disables the check.
+func WithMaxVersionGap(gap int64) MigratorOption {
+	return func(c *Config) {
+		c.MaxVersionGap = gap
+	}
+}
+
+// WithChecksumValidation enables or disables checksum validation. If it is enabled, a checksum mismatch of an applied
+// migration is an error, otherwise it is reported to the logger as a warning.
+func WithChecksumValidation(enabled bool) MigratorOption {
+	return func(c *Config) {
+		c.ChecksumValidation = enabled
+	}
+}
+
+// WithOutput sets a writer for human-readable progress lines, e.g.
+// `Applying migration 0005 (create_orders)... done (142ms)`.
+func WithOutput(w io.Writer) MigratorOption {
+	return func(c *Config) {
+		c.Output = w
+	}
+}
+
+// WithJSONOutput sets a writer for progress records in the newline-delimited JSON format.
+func WithJSONOutput(w io.Writer) MigratorOption {
+	return func(c *Config) {
+		c.JSONOutput = w
+	}
+}
+
+// WithDefaultTags restricts all operations to migrations which have at least one of the specified tags.
+// Rolling back requires all applied migrations to match the tags.
+func WithDefaultTags(tags []string) MigratorOption {
+	return func(c *Config) {
+		c.DefaultTags = tags
+	}
+}
+
+// WithVersionValidator sets a validator of migration versions. See `TimestampVersionValidator` and
+// `SequentialVersionValidator`.
+func WithVersionValidator(v VersionValidator) MigratorOption {
+	return func(c *Config) {
+		c.VersionValidator = v
+	}
+}
+
+// WithInterceptor adds an interceptor of migration steps. Interceptors are called in the order of addition.
+func WithInterceptor(i Interceptor) MigratorOption {
+	return func(c *Config) {
+		c.Interceptors = append(c.Interceptors, i)
+	}
+}
+
+// WithErrorStrategy sets the behavior of `Up` on a migration failure.
+func WithErrorStrategy(s ErrorStrategy) MigratorOption {
+	return func(c *Config) {
+		c.ErrorStrategy = s
+	}
+}
+
+// WithDefaultRetryPolicy sets the retry policy of migrations which have no own policy.
+// Only migration functions are retried, history records are never retried.
+func WithDefaultRetryPolicy(p migration.RetryPolicy) MigratorOption {
+	return func(c *Config) {
+		c.DefaultRetryPolicy = p
+	}
+}
+
+// WithAutoInit enables or disables automatic initialization of the migrations table on the first operation.
+func WithAutoInit(enabled bool) MigratorOption {
+	return func(c *Config) {
+		c.AutoInit = enabled
+	}
+}
+
+// WithMaxNameLength sets the maximum length of migration names checked by `Validate`. Zero means no limit.
+func WithMaxNameLength(n int) MigratorOption {
+	return func(c *Config) {
+		c.MaxNameLength = n
+	}
+}
+
+// WithStrictNoopCheck makes a migration which uses `DummyUpDown` as its `up` function an error of `Up`
+// instead of a warning.
+func WithStrictNoopCheck(enabled bool) MigratorOption {
+	return func(c *Config) {
+		c.StrictNoopCheck = enabled
+	}
+}
+
+// WithEnvPrefix enables reading of default command arguments from environment variables: `up` without a version
+// uses `<prefix>_TARGET_VERSION` and `set_version` without a version uses `<prefix>_SET_VERSION`.
+func WithEnvPrefix(prefix string) MigratorOption {
+	return func(c *Config) {
+		c.EnvPrefix = prefix
+	}
+}
+
+// WithWaitForDB makes `Init` and `Up` wait until the database is available, pinging it with the interval.
+// Zero interval disables waiting.
+func WithWaitForDB(interval time.Duration) MigratorOption {
+	return func(c *Config) {
+		c.WaitForDBInterval = interval
+	}
+}
+
+// WithRollbackOnError makes `Up` roll back the migrations applied by the same call if some migration fails.
+func WithRollbackOnError(enabled bool) MigratorOption {
+	return func(c *Config) {
+		c.RollbackOnError = enabled
+	}
+}
+
+// WithNoopWarning controls whether the warning about an applied migration which uses `DummyUpDown` as its `up`
+// function (e.g. `migration.NewNoopMigration`) is an error of `Up`. It is an alias of `WithStrictNoopCheck`.
+func WithNoopWarning(enabled bool) MigratorOption {
+	return WithStrictNoopCheck(enabled)
+}
+
+// WithEventChannel sets a channel which receives an event of every applied or rolled back migration.
+// Events are sent without blocking and dropped if the channel is not ready, so the channel must be buffered
+// if the consumer processes events asynchronously.
+func WithEventChannel(ch chan<- MigrationEvent) MigratorOption {
+	return func(c *Config) {
+		c.EventChannel = ch
+	}
+}
+
+// WithAutoMigrate enables or disables upgrading of the existing history table schema by `Init`
+// (e.g. adding new columns). It is enabled by default.
+func WithAutoMigrate(enabled bool) MigratorOption {
+	return func(c *Config) {
+		c.AutoMigrate = enabled
+	}
+}
+
+// WithWarnOnMissingDown sets a callback which is called by `Validate` for every migration whose `down` function
+// is nil or `DummyUpDown`.
+func WithWarnOnMissingDown(fn func(version int64, name string)) MigratorOption {
+	return func(c *Config) {
+		c.WarnOnMissingDown = fn
+	}
+}
+
+// WithSkipVersions makes `Up` skip pending migrations of the versions: they are neither applied nor recorded
+// in the history. It is a break-glass option for emergencies, e.g. to bypass a broken migration.
+func WithSkipVersions(versions ...int64) MigratorOption {
+	return func(c *Config) {
+		c.SkipVersions = append(c.SkipVersions, versions...)
+	}
+}
+
+// WithSemanticVersioning enables displaying of semantic versions (see `migration.SemanticToInt64`) in log messages
+// and statuses.
+func WithSemanticVersioning(enabled bool) MigratorOption {
+	return func(c *Config) {
+		c.SemanticVersioning = enabled
+	}
+}
+
+// WithDialectInit sets a function which is called by `Init` before the migrations table is created, e.g. to run
+// setup SQL of the dialect. Without this option `SQLiteDialectInit` is used for SQLite databases.
+func WithDialectInit(fn func(db *gorm.DB) error) MigratorOption {
+	return func(c *Config) {
+		c.DialectInit = fn
+	}
+}
+
+// WithForcedCurrentVersion makes `Version` return the version instead of querying the database and makes `Up`
+// apply only migrations which are newer than the version. It is an in-memory override which never changes
+// the history. The intended use case is blue-green deployments: the idle environment may hold back migrations
+// which must not be applied until the traffic is switched to it (see `Migrator.ClearForcedVersion`).
+func WithForcedCurrentVersion(version int64) MigratorOption {
+	return func(c *Config) {
+		c.ForcedCurrentVersion = &version
+	}
+}
+
+// WithGroupedMigrations enables or disables atomic application of migration groups: consecutive pending migrations
+// with the same non-empty `Group` are applied by `Up` inside a single transaction, so a failure of any of them rolls
+// back the whole group. Transactions of individual migrations (see `WithTransactionalMigrations`) become savepoints
+// of the group transaction. Members of a group are applied inside the transaction even if they set `NoTransaction`.
+func WithGroupedMigrations(enabled bool) MigratorOption {
+	return func(c *Config) {
+		c.GroupedMigrations = enabled
+	}
+}
+
+// WithAppliedBy sets the identifier of the process (e.g. a container or a deployment job) which is stored
+// in history records of applied migrations for audit purposes. The host is taken from `HOSTNAME` environment variable.
+func WithAppliedBy(identifier string) MigratorOption {
+	return func(c *Config) {
+		c.AppliedBy = identifier
+	}
+}
+
+// WithMigrationDB sets the DB instance which is passed to migration functions if the migrator keeps the history
+// in a custom store (see `NewMigratorWithStore`).
+func WithMigrationDB(db *gorm.DB) MigratorOption {
+	return func(c *Config) {
+		c.MigrationDB = db
+	}
+}
+
+// newConfig returns a configuration with default values modified by the options.
+func newConfig(opts ...MigratorOption) Config {
+	c := Config{
+		TableName:          DefaultMigrationTable,
+		Logger:             NoopLogger{},
+		LockStrategy:       lock.NoopLock{},
+		DefaultRetryPolicy: DefaultRetryPolicy,
+		AutoMigrate:        true,
+		AppliedHost:        os.Getenv("HOSTNAME"),
+	}
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return c
+}
+
+// qualifiedTableName returns the name of the migrations history table qualified with the schema if it is specified.
+func (c *Config) qualifiedTableName() string {
+	if c.Schema == "" {
+		return c.TableName
+	}
+
+	return c.Schema + "." + c.TableName
+}
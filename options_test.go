@@ -0,0 +1,120 @@
+package migrator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+func TestWithMigrationTableSeparatesHistories(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	first := NewMigrator(db, []migration.Migration{tableMigration(2), tableMigration(3)},
+		WithMigrationTable("app1_migrations"), WithAutoInit(true))
+	second := NewMigrator(db, []migration.Migration{tableMigration(4)},
+		WithMigrationTable("app2_migrations"), WithAutoInit(true))
+
+	if _, _, err := first.Up(ctx, -1); err != nil {
+		t.Fatalf("first Up() error = %v", err)
+	}
+
+	if _, _, err := second.Up(ctx, -1); err != nil {
+		t.Fatalf("second Up() error = %v", err)
+	}
+
+	for _, table := range []string{"app1_migrations", "app2_migrations"} {
+		if !db.Migrator().HasTable(table) {
+			t.Errorf("table %s does not exist", table)
+		}
+	}
+
+	if db.Migrator().HasTable(DefaultMigrationTable) {
+		t.Errorf("table %s exists", DefaultMigrationTable)
+	}
+
+	tests := []struct {
+		name    string
+		m       *Migrator
+		version int64
+		history []int64
+	}{
+		{"first", first, 3, []int64{1, 2, 3}},
+		{"second", second, 4, []int64{1, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, version, err := tt.m.Version(ctx)
+			if err != nil {
+				t.Fatalf("Version() error = %v", err)
+			}
+
+			if version != tt.version {
+				t.Errorf("Version() = %d, want %d", version, tt.version)
+			}
+
+			history, err := tt.m.History(ctx)
+			if err != nil {
+				t.Fatalf("History() error = %v", err)
+			}
+
+			if got := versionsOf(history); !reflect.DeepEqual(got, tt.history) {
+				t.Errorf("History() versions = %v, want %v", got, tt.history)
+			}
+		})
+	}
+
+	if _, _, err := first.Reset(ctx); err != nil {
+		t.Fatalf("first Reset() error = %v", err)
+	}
+
+	if _, version, _ := second.Version(ctx); version != 4 {
+		t.Errorf("second Version() after first Reset() = %d, want 4", version)
+	}
+}
+
+func TestSetVersionWithMigrationTable(t *testing.T) {
+	const table = "custom_migrations"
+
+	ctx := context.Background()
+	db := openTestDB(t)
+	m := NewMigrator(db, []migration.Migration{tableMigration(2), tableMigration(3), tableMigration(4)},
+		WithMigrationTable(table), WithAutoInit(true))
+
+	if _, _, err := m.Up(ctx, 2); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	oldVersion, newVersion, err := m.SetVersion(ctx, 4)
+	if err != nil {
+		t.Fatalf("SetVersion() error = %v", err)
+	}
+
+	if oldVersion != 2 || newVersion != 4 {
+		t.Errorf("SetVersion() = %d, %d, want 2, 4", oldVersion, newVersion)
+	}
+
+	history, err := m.History(ctx)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+
+	if got := versionsOf(history); !reflect.DeepEqual(got, []int64{1, 2, 3, 4}) {
+		t.Errorf("History() versions = %v, want [1 2 3 4]", got)
+	}
+
+	if _, _, err := m.SetVersion(ctx, 2); err != nil {
+		t.Fatalf("SetVersion() down error = %v", err)
+	}
+
+	if count := countRecords(t, db, table); count != 2 {
+		t.Errorf("records of %s = %d, want 2", table, count)
+	}
+
+	if db.Migrator().HasTable(DefaultMigrationTable) {
+		t.Errorf("table %s exists", DefaultMigrationTable)
+	}
+}
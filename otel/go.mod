@@ -0,0 +1,17 @@
+module github.com/Devoter/gorm-migrator/otel
+
+go 1.18
+
+require (
+	github.com/Devoter/gorm-migrator v0.0.0
+	go.opentelemetry.io/otel v1.11.2
+	go.opentelemetry.io/otel/trace v1.11.2
+	gorm.io/gorm v1.21.16
+)
+
+require (
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.2 // indirect
+)
+
+replace github.com/Devoter/gorm-migrator => ../
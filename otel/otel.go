@@ -0,0 +1,56 @@
+// Package otel provides OpenTelemetry tracing of migrations.
+package otel
+
+import (
+	"context"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	migrator "github.com/Devoter/gorm-migrator"
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// instrumentationName is the name of the tracer.
+const instrumentationName = "github.com/Devoter/gorm-migrator"
+
+// WithTracerProvider returns an option which wraps every migration step in a span named
+// `migration.<direction>.<version>`.
+func WithTracerProvider(tp trace.TracerProvider) migrator.MigratorOption {
+	return migrator.WithInterceptor(NewInterceptor(tp))
+}
+
+// NewInterceptor returns an interceptor which wraps every migration step in a span.
+func NewInterceptor(tp trace.TracerProvider) migrator.Interceptor {
+	tracer := tp.Tracer(instrumentationName)
+
+	return func(ctx context.Context, db *gorm.DB, migr migration.Migration, direction string, next migrator.StepFunc) error {
+		attrs := []attribute.KeyValue{
+			attribute.Int64("migration.version", migr.Version),
+			attribute.String("migration.name", migr.Name),
+			attribute.String("migration.direction", direction),
+		}
+
+		if db != nil && db.Dialector != nil {
+			attrs = append(attrs, attribute.String("db.system", db.Dialector.Name()))
+		}
+
+		ctx, span := tracer.Start(ctx, "migration."+direction+"."+strconv.FormatInt(migr.Version, 10),
+			trace.WithAttributes(attrs...))
+		defer span.End()
+
+		err := next(ctx)
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+
+		return err
+	}
+}
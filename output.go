@@ -0,0 +1,61 @@
+package migrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// outputRecord declares a JSON progress record.
+type outputRecord struct {
+	Version    int64  `json:"version"`
+	Name       string `json:"name"`
+	Direction  string `json:"direction"`
+	Success    bool   `json:"success"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// writeStarted writes the beginning of the human-readable progress line.
+func (m *Migrator) writeStarted(migr *migration.Migration, direction string) {
+	if m.config.Output == nil {
+		return
+	}
+
+	action := "Applying"
+
+	if direction == DirectionDown {
+		action = "Rolling back"
+	}
+
+	fmt.Fprintf(m.config.Output, "%s migration %04d (%s)... ", action, migr.Version, migr.Name)
+}
+
+// writeCompleted writes the end of the human-readable progress line and the JSON progress record.
+func (m *Migrator) writeCompleted(migr *migration.Migration, direction string, elapsed time.Duration, err error) {
+	if m.config.Output != nil {
+		if err != nil {
+			fmt.Fprintf(m.config.Output, "failed (%dms): %v\n", elapsed.Milliseconds(), err)
+		} else {
+			fmt.Fprintf(m.config.Output, "done (%dms)\n", elapsed.Milliseconds())
+		}
+	}
+
+	if m.config.JSONOutput != nil {
+		record := outputRecord{
+			Version:    migr.Version,
+			Name:       migr.Name,
+			Direction:  direction,
+			Success:    err == nil,
+			DurationMs: elapsed.Milliseconds(),
+		}
+
+		if err != nil {
+			record.Error = err.Error()
+		}
+
+		_ = json.NewEncoder(m.config.JSONOutput).Encode(record)
+	}
+}
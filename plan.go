@@ -0,0 +1,333 @@
+package migrator
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// PlanUp returns a list of migrations which would be applied by `Up` with the same target.
+func (m *Migrator) PlanUp(ctx context.Context, target int64) (plan []migration.Migration, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, plan, _, err = m.planUp(m.session(ctx), target, -1)
+	return
+}
+
+// PlanDown returns a list of migrations which would be rolled back by `Down`.
+func (m *Migrator) PlanDown(ctx context.Context) (plan []migration.Migration, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, plan, err = m.planDown(m.session(ctx))
+	return
+}
+
+// Pending returns a sorted list of registered migrations which are not applied yet.
+// An empty list means that the database is up to date.
+func (m *Migrator) Pending(ctx context.Context) (pending []migration.Migration, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, pending, _, err = m.planUp(m.session(ctx), -1, -1)
+	return
+}
+
+// planUp returns the planned result, a sorted list of pending migrations up to the target version and a list
+// of pending versions which are skipped by the configuration. Pending dependencies of the planned migrations
+// are planned too even if they are newer than the target. Migrations are sorted by dependencies and then
+// by version. A negative `limit` means no limit of migrations count.
+func (m *Migrator) planUp(db *gorm.DB, target int64, limit int) (res Result, plan []migration.Migration, skipped []int64, err error) {
+	var history []migration.Migration
+
+	if history, err = m.loadHistory(db); err != nil {
+		return
+	}
+
+	if length := len(history); length > 0 {
+		res.OldVersion = history[length-1].Version
+		res.NewVersion = res.OldVersion
+	}
+
+	forced := m.config.ForcedCurrentVersion
+
+	if forced != nil {
+		res.OldVersion = *forced
+		res.NewVersion = *forced
+	}
+
+	if err = m.verifyChecksums(history, m.migrations); err != nil {
+		return
+	}
+
+	merged := m.mergeMigrations(history, m.migrations, target)
+	pending := []migration.Migration{}
+
+	for _, migr := range merged {
+		if migr.Stored {
+			res.Skipped = append(res.Skipped, migr)
+		} else if forced != nil && migr.Version <= *forced {
+			// held back by the forced current version
+			continue
+		} else if m.isSkippedVersion(migr.Version) {
+			skipped = append(skipped, migr.Version)
+		} else {
+			pending = append(pending, migr)
+		}
+	}
+
+	if pending, err = m.withDependencies(history, pending); err != nil {
+		return
+	}
+
+	if pending, err = migration.SortByDependencies(pending); err != nil {
+		return
+	}
+
+	plan = []migration.Migration{}
+
+	for _, migr := range pending {
+		if limit == 0 {
+			break
+		}
+
+		plan = append(plan, migr)
+		limit--
+
+		if migr.Version > res.NewVersion {
+			res.NewVersion = migr.Version
+		}
+	}
+
+	return
+}
+
+// withDependencies returns the pending migrations with all their unapplied dependencies. It returns
+// `DependencyError` if some dependency is not registered, is skipped by the configuration or is held back
+// by the forced current version.
+func (m *Migrator) withDependencies(history, pending []migration.Migration) ([]migration.Migration, error) {
+	planned := make(map[int64]bool, len(history)+len(pending))
+
+	for _, migr := range history {
+		planned[migr.Version] = true
+	}
+
+	for _, migr := range pending {
+		planned[migr.Version] = true
+	}
+
+	forced := m.config.ForcedCurrentVersion
+
+	// the list grows while dependencies are added
+	for i := 0; i < len(pending); i++ {
+		for _, dep := range pending[i].DependsOn {
+			if planned[dep] {
+				continue
+			}
+
+			migr, ok := migration.Find(m.migrations, dep)
+			if !ok || m.isSkippedVersion(dep) || (forced != nil && dep <= *forced) {
+				return nil, &DependencyError{Version: pending[i].Version, DependsOn: dep}
+			}
+
+			planned[dep] = true
+			pending = append(pending, migr)
+		}
+	}
+
+	return pending, nil
+}
+
+// checkDependents returns `DependencyError` if some applied migration which is not planned to be rolled back
+// depends on a planned one.
+func (m *Migrator) checkDependents(applied, plan []migration.Migration) error {
+	rolledBack := make(map[int64]bool, len(plan))
+
+	for _, migr := range plan {
+		rolledBack[migr.Version] = true
+	}
+
+	for _, migr := range applied {
+		if rolledBack[migr.Version] {
+			continue
+		}
+
+		// history records have no dependencies, so they are taken from registered migrations
+		if registered, ok := migration.Find(m.migrations, migr.Version); ok {
+			for _, dep := range registered.DependsOn {
+				if rolledBack[dep] {
+					return &DependencyError{Version: migr.Version, DependsOn: dep}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// sortDownPlan orders the plan so that every migration is rolled back before its dependencies.
+// Independent migrations are ordered by version descending.
+func sortDownPlan(plan []migration.Migration) (sorted []migration.Migration, err error) {
+	if sorted, err = migration.SortByDependencies(plan); err != nil {
+		return
+	}
+
+	for i, j := 0, len(sorted)-1; i < j; i, j = i+1, j-1 {
+		sorted[i], sorted[j] = sorted[j], sorted[i]
+	}
+
+	return
+}
+
+// planDown returns the planned result and a list which contains the current migration. The new version
+// is the version of the previous applied migration, so gaps of the history (e.g. skipped migrations) are respected.
+// The plan is empty if the current migration is not registered.
+func (m *Migrator) planDown(db *gorm.DB) (res Result, plan []migration.Migration, err error) {
+	var old migration.Migration
+
+	if old, err = m.historyStore(db).LastMigration(); err != nil {
+		return
+	}
+
+	res.OldVersion = old.Version
+	res.NewVersion = old.Version
+	plan = []migration.Migration{}
+
+	mig, ok := migration.Find(m.migrations, old.Version)
+	if !ok || mig.Version == m.migrations[0].Version {
+		return
+	}
+
+	var history []migration.Migration
+
+	if history, err = m.loadHistory(db); err != nil {
+		return
+	}
+
+	plan = append(plan, mig)
+
+	if err = m.checkDependents(history, plan); err != nil {
+		return
+	}
+
+	res.NewVersion = 0
+
+	for _, applied := range history {
+		if applied.Version < old.Version && applied.Version > res.NewVersion {
+			res.NewVersion = applied.Version
+		}
+	}
+
+	return
+}
+
+// planDownN returns the planned result and a list of `n` last applied migrations in the descending order.
+// The initial version is never included.
+func (m *Migrator) planDownN(db *gorm.DB, n int) (res Result, plan []migration.Migration, err error) {
+	var correlated []migration.Migration
+
+	res.OldVersion, correlated, err = m.correlatedHistory(db)
+	res.NewVersion = res.OldVersion
+
+	if err != nil {
+		return
+	}
+
+	plan = []migration.Migration{}
+
+	for i := len(correlated) - 1; i > 0 && n > 0; i-- {
+		if correlated[i].Version <= 1 {
+			break
+		}
+
+		plan = append(plan, correlated[i])
+		res.NewVersion = correlated[i-1].Version
+		n--
+	}
+
+	if err = m.checkDependents(correlated, plan); err != nil {
+		res.NewVersion = res.OldVersion
+		return
+	}
+
+	plan, err = sortDownPlan(plan)
+
+	return
+}
+
+// planDownTo returns the planned result and a list of applied migrations which are newer than the target version
+// in the descending order.
+func (m *Migrator) planDownTo(db *gorm.DB, target int64) (res Result, plan []migration.Migration, err error) {
+	if !m.Contains(target) {
+		err = ErrorTargetVersionNotFound
+		return
+	}
+
+	var correlated []migration.Migration
+
+	res.OldVersion, correlated, err = m.correlatedHistory(db)
+	res.NewVersion = res.OldVersion
+
+	if err != nil {
+		return
+	}
+
+	plan = []migration.Migration{}
+
+	for i := len(correlated) - 1; i > 0 && correlated[i].Version > target; i-- {
+		plan = append(plan, correlated[i])
+		res.NewVersion = correlated[i-1].Version
+	}
+
+	if err = m.checkDependents(correlated, plan); err != nil {
+		res.NewVersion = res.OldVersion
+		return
+	}
+
+	plan, err = sortDownPlan(plan)
+
+	return
+}
+
+// planReset returns the planned result and a list of all applied migrations in the descending order.
+func (m *Migrator) planReset(db *gorm.DB) (res Result, plan []migration.Migration, err error) {
+	var correlated []migration.Migration
+
+	res.OldVersion, correlated, err = m.correlatedHistory(db)
+	res.NewVersion = res.OldVersion
+
+	if err != nil {
+		return
+	}
+
+	plan = make([]migration.Migration, 0, len(correlated))
+
+	for i := len(correlated) - 1; i >= 0; i-- {
+		plan = append(plan, correlated[i])
+		res.NewVersion = correlated[i].Version
+	}
+
+	plan, err = sortDownPlan(plan)
+
+	return
+}
+
+// correlatedHistory returns the current version and a list of applied migrations correlated with actual migrations.
+func (m *Migrator) correlatedHistory(db *gorm.DB) (version int64, correlated []migration.Migration, err error) {
+	var history []migration.Migration
+
+	if history, err = m.loadHistory(db); err != nil {
+		return
+	}
+
+	if length := len(history); length > 0 {
+		version = history[length-1].Version
+	}
+
+	correlated, err = m.correlateMigrations(history, m.migrations)
+
+	return
+}
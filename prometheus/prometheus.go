@@ -0,0 +1,114 @@
+// Package prometheus provides Prometheus metrics of migrations.
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+
+	migrator "github.com/Devoter/gorm-migrator"
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// DefaultNamespace is the default prefix of metric names.
+const DefaultNamespace = "migrator"
+
+// Metrics declares migration metrics: `migrations_applied_total` and `migration_failures_total` counters labeled
+// by the direction (`up` or `down`) and the `migration_duration_seconds` histogram labeled by the version.
+// The direction label of the applied counter keeps rolled back migrations apart from applied ones.
+type Metrics struct {
+	applied  *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	failures *prometheus.CounterVec
+}
+
+// NewMetrics returns a new instance of Metrics with names prefixed by the namespace.
+func NewMetrics(namespace string) *Metrics {
+	return &Metrics{
+		applied: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "migrations_applied_total",
+			Help:      "Total number of successfully applied (up) or rolled back (down) migrations.",
+		}, []string{"direction"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "migration_duration_seconds",
+			Help:      "Duration of migrations in seconds.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"version"}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "migration_failures_total",
+			Help:      "Total number of failed migrations.",
+		}, []string{"direction"}),
+	}
+}
+
+// Register registers metrics. Already registered metrics with the same names are reused, so metrics
+// can be shared between several migrators.
+func (m *Metrics) Register(reg prometheus.Registerer) error {
+	var err error
+
+	if err = register(reg, m.applied, func(c prometheus.Collector) { m.applied = c.(*prometheus.CounterVec) }); err != nil {
+		return err
+	}
+
+	if err = register(reg, m.duration, func(c prometheus.Collector) { m.duration = c.(*prometheus.HistogramVec) }); err != nil {
+		return err
+	}
+
+	return register(reg, m.failures, func(c prometheus.Collector) { m.failures = c.(*prometheus.CounterVec) })
+}
+
+// Interceptor returns an interceptor which updates metrics for every migration step.
+func (m *Metrics) Interceptor() migrator.Interceptor {
+	return func(ctx context.Context, db *gorm.DB, migr migration.Migration, direction string, next migrator.StepFunc) error {
+		started := time.Now()
+		err := next(ctx)
+
+		m.duration.WithLabelValues(strconv.FormatInt(migr.Version, 10)).Observe(time.Since(started).Seconds())
+
+		if err != nil {
+			m.failures.WithLabelValues(direction).Inc()
+		} else {
+			m.applied.WithLabelValues(direction).Inc()
+		}
+
+		return err
+	}
+}
+
+// WithPrometheusRegisterer returns an option which registers metrics with the default namespace
+// and updates them for every migration step. It panics if metrics cannot be registered.
+func WithPrometheusRegisterer(reg prometheus.Registerer) migrator.MigratorOption {
+	return WithNamespacedRegisterer(reg, DefaultNamespace)
+}
+
+// WithNamespacedRegisterer is like `WithPrometheusRegisterer` but uses the specified namespace.
+func WithNamespacedRegisterer(reg prometheus.Registerer, namespace string) migrator.MigratorOption {
+	metrics := NewMetrics(namespace)
+
+	if err := metrics.Register(reg); err != nil {
+		panic(err)
+	}
+
+	return migrator.WithInterceptor(metrics.Interceptor())
+}
+
+// register registers the collector or passes the existing one to `reuse` if it is already registered.
+func register(reg prometheus.Registerer, c prometheus.Collector, reuse func(c prometheus.Collector)) error {
+	err := reg.Register(c)
+
+	var already prometheus.AlreadyRegisteredError
+
+	if errors.As(err, &already) {
+		reuse(already.ExistingCollector)
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,88 @@
+package prometheus
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	migrator "github.com/Devoter/gorm-migrator"
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+func TestInterceptorMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("get database: %v", err)
+	}
+
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	reg := prometheus.NewRegistry()
+	m := migrator.NewMigrator(db, []migration.Migration{
+		migration.NewSQLMigration(2, "create_t2", "CREATE TABLE t2 (id integer)", "DROP TABLE t2"),
+		migration.NewSQLMigration(3, "create_t3", "CREATE TABLE t3 (id integer)", "DROP TABLE t3"),
+	}, WithNamespacedRegisterer(reg, "test"), migrator.WithAutoInit(true))
+
+	if _, _, err := m.Up(ctx, -1); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	if _, _, err := m.Down(ctx); err != nil {
+		t.Fatalf("Down() error = %v", err)
+	}
+
+	applied := `
+# HELP test_migrations_applied_total Total number of successfully applied (up) or rolled back (down) migrations.
+# TYPE test_migrations_applied_total counter
+test_migrations_applied_total{direction="down"} 1
+test_migrations_applied_total{direction="up"} 2
+`
+
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(applied), "test_migrations_applied_total"); err != nil {
+		t.Error(err)
+	}
+
+	// the version 2 is applied, the version 3 is applied and rolled back
+	if count := testutil.CollectAndCount(reg, "test_migration_duration_seconds"); count != 2 {
+		t.Errorf("duration series = %d, want 2", count)
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+
+	samples := map[string]uint64{}
+
+	for _, family := range families {
+		if family.GetName() != "test_migration_duration_seconds" {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			samples[metric.GetLabel()[0].GetValue()] = metric.GetHistogram().GetSampleCount()
+		}
+	}
+
+	if samples["2"] != 1 || samples["3"] != 2 {
+		t.Errorf("duration sample counts = %v, want 2: 1, 3: 2", samples)
+	}
+
+	if count := testutil.CollectAndCount(reg, "test_migration_failures_total"); count != 0 {
+		t.Errorf("failure series = %d, want 0", count)
+	}
+}
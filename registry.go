@@ -0,0 +1,72 @@
+package migrator
+
+import (
+	"sort"
+	"sync"
+
+	"gorm.io/gorm"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+var registry = struct {
+	sync.Mutex
+	migrations []migration.Migration
+}{}
+
+// Register adds migrations to the global registry. It is intended to be called from `init` functions.
+// Register panics if some version is already registered.
+func Register(migrations ...migration.Migration) {
+	if err := RegisterE(migrations...); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterE adds migrations to the global registry. It returns `ErrorDuplicateVersion` wrapped into `VersionError`
+// if some version is already registered. Nothing is registered in this case.
+func RegisterE(migrations ...migration.Migration) error {
+	registry.Lock()
+	defer registry.Unlock()
+
+	versions := make(map[int64]bool, len(registry.migrations)+len(migrations))
+
+	for _, migr := range registry.migrations {
+		versions[migr.Version] = true
+	}
+
+	for _, migr := range migrations {
+		if versions[migr.Version] {
+			return &VersionError{Version: migr.Version, Err: ErrorDuplicateVersion}
+		}
+
+		versions[migr.Version] = true
+	}
+
+	registry.migrations = append(registry.migrations, migrations...)
+
+	return nil
+}
+
+// Registered returns a sorted copy of the registered migrations.
+func Registered() []migration.Migration {
+	registry.Lock()
+	defer registry.Unlock()
+
+	migrations := make([]migration.Migration, len(registry.migrations))
+	copy(migrations, registry.migrations)
+	sort.Sort(migration.Migrations(migrations))
+
+	return migrations
+}
+
+// NewMigratorFromRegistry returns a new instance of Migrator with the registered migrations.
+// It panics if the registered migrations are invalid (see `Validate`).
+func NewMigratorFromRegistry(db *gorm.DB, opts ...MigratorOption) *Migrator {
+	m := NewMigrator(db, Registered(), opts...)
+
+	if err := m.Validate(); err != nil {
+		panic(err)
+	}
+
+	return m
+}
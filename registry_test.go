@@ -0,0 +1,93 @@
+package migrator
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// resetRegistry empties the global registry and restores it when the test finishes.
+func resetRegistry(t *testing.T) {
+	registry.Lock()
+	saved := registry.migrations
+	registry.migrations = nil
+	registry.Unlock()
+
+	t.Cleanup(func() {
+		registry.Lock()
+		registry.migrations = saved
+		registry.Unlock()
+	})
+}
+
+func TestRegisterDuplicatePanics(t *testing.T) {
+	resetRegistry(t)
+	Register(tableMigration(2))
+
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, ErrorDuplicateVersion) {
+			t.Errorf("recovered %v, want %v", err, ErrorDuplicateVersion)
+		}
+	}()
+
+	Register(tableMigration(2))
+}
+
+func TestRegisterE(t *testing.T) {
+	resetRegistry(t)
+
+	if err := RegisterE(tableMigration(3), tableMigration(2)); err != nil {
+		t.Fatalf("RegisterE() error = %v", err)
+	}
+
+	var versionErr *VersionError
+
+	if err := RegisterE(tableMigration(4), tableMigration(3)); !errors.As(err, &versionErr) || !errors.Is(err, ErrorDuplicateVersion) {
+		t.Errorf("RegisterE() error = %v, want %v of version 3", err, ErrorDuplicateVersion)
+	} else if versionErr.Version != 3 {
+		t.Errorf("RegisterE() error version = %d, want 3", versionErr.Version)
+	}
+
+	if err := RegisterE(tableMigration(5), tableMigration(5)); !errors.Is(err, ErrorDuplicateVersion) {
+		t.Errorf("RegisterE() of a duplicate within the call error = %v, want %v", err, ErrorDuplicateVersion)
+	}
+
+	// failed calls register nothing
+	if got := versionsOf(Registered()); !reflect.DeepEqual(got, []int64{2, 3}) {
+		t.Errorf("Registered() versions = %v, want [2 3]", got)
+	}
+}
+
+func TestRegisterConcurrent(t *testing.T) {
+	resetRegistry(t)
+
+	var wg sync.WaitGroup
+
+	for i := int64(2); i < 12; i++ {
+		wg.Add(1)
+
+		go func(version int64) {
+			defer wg.Done()
+			Register(tableMigration(version))
+		}(i)
+	}
+
+	wg.Wait()
+
+	if got := versionsOf(Registered()); !reflect.DeepEqual(got, []int64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11}) {
+		t.Errorf("Registered() versions = %v, want 2..11", got)
+	}
+}
+
+func TestNewMigratorFromRegistry(t *testing.T) {
+	resetRegistry(t)
+	Register(tableMigration(3), tableMigration(2))
+
+	m := NewMigratorFromRegistry(openTestDB(t))
+
+	if got := versionsOf(m.migrations); !reflect.DeepEqual(got, []int64{1, 2, 3}) {
+		t.Errorf("migrations versions = %v, want [1 2 3]", got)
+	}
+}
@@ -0,0 +1,159 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// RepairActionType declares a type of a history repair action.
+type RepairActionType int
+
+const (
+	// RepairInsert means that a history record of a registered migration should be inserted.
+	RepairInsert RepairActionType = iota
+	// RepairDelete means that a history record of an unregistered migration should be excluded from applied ones.
+	RepairDelete
+)
+
+func (t RepairActionType) String() string {
+	switch t {
+	case RepairInsert:
+		return "insert"
+	case RepairDelete:
+		return "delete"
+	default:
+		return "unknown"
+	}
+}
+
+// RepairAction declares a fix of the migrations history.
+type RepairAction struct {
+	Type    RepairActionType
+	Version int64
+	Name    string
+}
+
+func (a RepairAction) String() string {
+	return fmt.Sprintf("%s history record %04d (%s)", a.Type, a.Version, a.Name)
+}
+
+// PlanRepair returns a list of actions which would be applied by `Repair`.
+func (m *Migrator) PlanRepair(ctx context.Context) (actions []RepairAction, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.planRepair(ctx)
+}
+
+// Repair reconciles the migrations history with registered migrations. History records of unregistered migrations
+// are deleted by `DeleteMigration` of the store, so GORMStore keeps them along with records of the rollback
+// until the history is compacted (see `CompactHistory`). Registered migrations which are older than the current version but have
+// no history records are considered applied and their records are inserted, except versions skipped
+// by `WithSkipVersions`. Migration functions are never called.
+func (m *Migrator) Repair(ctx context.Context) (actions []RepairAction, err error) {
+	err = m.withLock(ctx, func() (err error) {
+		actions, err = m.repair(ctx)
+		return
+	})
+
+	return
+}
+
+func (m *Migrator) repair(ctx context.Context) (actions []RepairAction, err error) {
+	if actions, err = m.planRepair(ctx); err != nil || m.config.DryRun {
+		return
+	}
+
+	store := m.historyStore(m.session(ctx))
+
+	for _, action := range actions {
+		if action.Type == RepairDelete {
+			err = store.DeleteMigration(action.Version)
+		} else {
+			// the registered migration is copied, so the checksum covers its SQL statements
+			migr, _ := migration.Find(m.migrations, action.Version)
+			migr.Checksum = migr.ComputeChecksum()
+			migr.AppliedDirection = DirectionUp
+			migr.AppliedAt = time.Now().UTC()
+			m.setAppliedBy(&migr)
+			err = store.InsertMigration(migr)
+		}
+
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// planRepair returns a sorted list of history repair actions.
+func (m *Migrator) planRepair(ctx context.Context) (actions []RepairAction, err error) {
+	var statuses []MigrationStatus
+
+	if statuses, err = m.status(ctx); err != nil {
+		return
+	}
+
+	// the latest applied registered migration, unregistered ones are going to be deleted
+	var version int64
+
+	for _, status := range statuses {
+		if status.State == Applied {
+			version = status.Version
+		}
+	}
+
+	actions = []RepairAction{}
+
+	for _, status := range statuses {
+		switch {
+		case status.State == Missing:
+			actions = append(actions, RepairAction{Type: RepairDelete, Version: status.Version, Name: status.Name})
+		case status.State == Pending && status.Version < version && !m.isSkippedVersion(status.Version):
+			actions = append(actions, RepairAction{Type: RepairInsert, Version: status.Version, Name: status.Name})
+		}
+	}
+
+	return
+}
+
+// runRepair interprets `repair [--dry-run]` command and writes actions to the output.
+func (m *Migrator) runRepair(ctx context.Context, dryRun bool) (oldVersion int64, newVersion int64, err error) {
+	dryRun = dryRun || m.config.DryRun
+
+	if oldVersion, newVersion, err = m.Version(ctx); err != nil {
+		return
+	}
+
+	var actions []RepairAction
+
+	if dryRun {
+		actions, err = m.PlanRepair(ctx)
+	} else {
+		actions, err = m.Repair(ctx)
+	}
+
+	if err != nil {
+		return
+	}
+
+	if m.config.Output != nil {
+		for _, action := range actions {
+			if dryRun {
+				fmt.Fprintf(m.config.Output, "Would %s\n", action)
+			} else {
+				fmt.Fprintf(m.config.Output, "Repaired: %s\n", action)
+			}
+		}
+	}
+
+	if !dryRun {
+		_, newVersion, err = m.Version(ctx)
+	}
+
+	return
+}
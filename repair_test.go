@@ -0,0 +1,118 @@
+package migrator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+func TestRepairInsertsRegisteredMigration(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	migrations := []migration.Migration{
+		migration.NewSQLMigration(2, "create_t2", "CREATE TABLE t2 (id integer)", "DROP TABLE t2"),
+		migration.NewSQLMigration(3, "create_t3", "CREATE TABLE t3 (id integer)", "DROP TABLE t3"),
+	}
+	m := NewMigrator(db, migrations, WithAutoInit(true), WithChecksumValidation(true))
+
+	if _, _, err := m.Up(ctx, -1); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	if err := db.Exec("DELETE FROM "+DefaultMigrationTable+" WHERE version = ?", 2).Error; err != nil {
+		t.Fatalf("delete record: %v", err)
+	}
+
+	actions, err := m.Repair(ctx)
+	if err != nil {
+		t.Fatalf("Repair() error = %v", err)
+	}
+
+	want := []RepairAction{{Type: RepairInsert, Version: 2, Name: "create_t2"}}
+
+	if !reflect.DeepEqual(actions, want) {
+		t.Errorf("Repair() = %v, want %v", actions, want)
+	}
+
+	// the inserted record matches the checksum of the SQL migration
+	if _, _, err := m.Up(ctx, -1); err != nil {
+		t.Errorf("Up() after Repair() error = %v", err)
+	}
+
+	var record migration.Migration
+
+	if err := db.Table(DefaultMigrationTable).Where("version = ?", 2).First(&record).Error; err != nil {
+		t.Fatalf("find record: %v", err)
+	}
+
+	if record.AppliedDirection != DirectionUp || record.AppliedAt.IsZero() {
+		t.Errorf("record = %q at %v, want an applied record", record.AppliedDirection, record.AppliedAt)
+	}
+}
+
+func TestRepairSkipsSkippedVersions(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	m := NewMigrator(db, []migration.Migration{tableMigration(2), tableMigration(3)},
+		WithAutoInit(true), WithSkipVersions(2))
+
+	if _, _, err := m.Up(ctx, -1); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	if db.Migrator().HasTable("t2") {
+		t.Fatal("table t2 of the skipped migration exists")
+	}
+
+	actions, err := m.Repair(ctx)
+	if err != nil {
+		t.Fatalf("Repair() error = %v", err)
+	}
+
+	if len(actions) != 0 {
+		t.Errorf("Repair() = %v, want no actions", actions)
+	}
+
+	history, err := m.History(ctx)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+
+	if got := versionsOf(history); !reflect.DeepEqual(got, []int64{1, 3}) {
+		t.Errorf("History() versions = %v, want [1 3]", got)
+	}
+}
+
+func TestRepairDeletesUnregisteredMigration(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	newer := NewMigrator(db, []migration.Migration{tableMigration(2), tableMigration(3)}, WithAutoInit(true))
+
+	if _, _, err := newer.Up(ctx, -1); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	older := NewMigrator(db, []migration.Migration{tableMigration(2)})
+
+	planned, err := older.PlanRepair(ctx)
+	if err != nil {
+		t.Fatalf("PlanRepair() error = %v", err)
+	}
+
+	actions, err := older.Repair(ctx)
+	if err != nil {
+		t.Fatalf("Repair() error = %v", err)
+	}
+
+	want := []RepairAction{{Type: RepairDelete, Version: 3, Name: "create_t3"}}
+
+	if !reflect.DeepEqual(planned, want) || !reflect.DeepEqual(actions, want) {
+		t.Errorf("PlanRepair(), Repair() = %v, %v, want %v", planned, actions, want)
+	}
+
+	if _, version, err := older.Version(ctx); err != nil || version != 2 {
+		t.Errorf("Version() = %d, %v, want 2, nil", version, err)
+	}
+}
@@ -0,0 +1,13 @@
+package migrator
+
+import "github.com/Devoter/gorm-migrator/migration"
+
+// Result declares a detailed result of a migrations operation.
+// `Applied` contains migrations which were applied or rolled back by the operation,
+// `Skipped` contains already applied migrations which were skipped.
+type Result struct {
+	OldVersion int64
+	NewVersion int64
+	Applied    []migration.Migration
+	Skipped    []migration.Migration
+}
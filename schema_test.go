@@ -0,0 +1,114 @@
+package migrator
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// postgresDSNEnv is the environment variable with the DSN of the PostgreSQL database used by integration tests.
+const postgresDSNEnv = "MIGRATOR_POSTGRES_DSN"
+
+// openPostgresDB returns a connection to the PostgreSQL database of integration tests through the pgx driver.
+// The test is skipped if the database is not configured.
+func openPostgresDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv(postgresDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", postgresDSNEnv)
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("get database: %v", err)
+	}
+
+	t.Cleanup(func() { sqlDB.Close() })
+
+	return db
+}
+
+func TestWithSchemaSeparatesHistories(t *testing.T) {
+	ctx := context.Background()
+	db := openPostgresDB(t)
+	schemas := []string{"migrator_tenant_a", "migrator_tenant_b"}
+
+	for _, schema := range schemas {
+		schema := schema
+
+		if err := db.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+			t.Fatalf("create schema %s: %v", schema, err)
+		}
+
+		t.Cleanup(func() { db.Exec("DROP SCHEMA " + schema + " CASCADE") })
+	}
+
+	tenantA := NewMigrator(db, []migration.Migration{
+		migration.NewSQLMigration(2, "create_a2", "CREATE TABLE migrator_tenant_a.a2 (id integer)", "DROP TABLE migrator_tenant_a.a2"),
+		migration.NewSQLMigration(3, "create_a3", "CREATE TABLE migrator_tenant_a.a3 (id integer)", "DROP TABLE migrator_tenant_a.a3"),
+	}, WithSchema(schemas[0]), WithAutoInit(true))
+	tenantB := NewMigrator(db, []migration.Migration{
+		migration.NewSQLMigration(2, "create_b2", "CREATE TABLE migrator_tenant_b.b2 (id integer)", "DROP TABLE migrator_tenant_b.b2"),
+	}, WithSchema(schemas[1]), WithAutoInit(true))
+
+	if _, _, err := tenantA.Up(ctx, -1); err != nil {
+		t.Fatalf("tenant A Up() error = %v", err)
+	}
+
+	if _, _, err := tenantB.Up(ctx, -1); err != nil {
+		t.Fatalf("tenant B Up() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		m       *Migrator
+		version int64
+		history []int64
+	}{
+		{"tenant A", tenantA, 3, []int64{1, 2, 3}},
+		{"tenant B", tenantB, 2, []int64{1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, version, err := tt.m.Version(ctx)
+			if err != nil {
+				t.Fatalf("Version() error = %v", err)
+			}
+
+			if version != tt.version {
+				t.Errorf("Version() = %d, want %d", version, tt.version)
+			}
+
+			history, err := tt.m.History(ctx)
+			if err != nil {
+				t.Fatalf("History() error = %v", err)
+			}
+
+			if got := versionsOf(history); !reflect.DeepEqual(got, tt.history) {
+				t.Errorf("History() versions = %v, want %v", got, tt.history)
+			}
+		})
+	}
+
+	if _, _, err := tenantA.SetVersion(ctx, 2); err != nil {
+		t.Fatalf("tenant A SetVersion() error = %v", err)
+	}
+
+	if _, version, _ := tenantB.Version(ctx); version != 2 {
+		t.Errorf("tenant B Version() after tenant A SetVersion() = %d, want 2", version)
+	}
+}
@@ -0,0 +1,44 @@
+package migrator
+
+import "sync"
+
+// skippedState declares a list of versions skipped by the last `Up` which is shared by copies of the migrator.
+type skippedState struct {
+	sync.Mutex
+	versions []int64
+}
+
+// SkippedVersions returns a sorted list of pending versions which were skipped by the last `Up`
+// according to `WithSkipVersions`.
+func (m *Migrator) SkippedVersions() []int64 {
+	m.skipped.Lock()
+	defer m.skipped.Unlock()
+
+	versions := make([]int64, len(m.skipped.versions))
+	copy(versions, m.skipped.versions)
+
+	return versions
+}
+
+// isSkippedVersion returns `true` if the version is skipped by the configuration.
+func (m *Migrator) isSkippedVersion(version int64) bool {
+	for _, v := range m.config.SkipVersions {
+		if v == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setSkipped reports skipped versions to the logger and saves them as skipped by the last `Up`.
+func (m *Migrator) setSkipped(versions []int64) {
+	for _, version := range versions {
+		m.config.Logger.Info("WARNING: migration is skipped", "version", version)
+	}
+
+	m.skipped.Lock()
+	defer m.skipped.Unlock()
+
+	m.skipped.versions = versions
+}
@@ -0,0 +1,106 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+func TestSkipVersions(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	migrations := []migration.Migration{tableMigration(2), tableMigration(3), tableMigration(4)}
+	m := NewMigrator(db, migrations, WithAutoInit(true), WithSkipVersions(3))
+
+	if got := m.SkippedVersions(); len(got) != 0 {
+		t.Errorf("SkippedVersions() before Up = %v, want []", got)
+	}
+
+	res, err := m.UpResult(ctx, -1)
+	if err != nil {
+		t.Fatalf("UpResult() error = %v", err)
+	}
+
+	if got := versionsOf(res.Applied); res.NewVersion != 4 || !reflect.DeepEqual(got, []int64{2, 4}) {
+		t.Errorf("UpResult() = %d, %v, want 4, [2 4]", res.NewVersion, got)
+	}
+
+	skipped := m.SkippedVersions()
+	if !reflect.DeepEqual(skipped, []int64{3}) {
+		t.Fatalf("SkippedVersions() = %v, want [3]", skipped)
+	}
+
+	// the result is a copy
+	skipped[0] = 0
+
+	if got := m.SkippedVersions(); !reflect.DeepEqual(got, []int64{3}) {
+		t.Errorf("SkippedVersions() after modification = %v, want [3]", got)
+	}
+
+	if db.Migrator().HasTable("t3") {
+		t.Error("skipped migration is applied")
+	}
+
+	// the skipped migration stays pending
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+
+	for _, status := range statuses {
+		if status.Version == 3 && status.State != Pending {
+			t.Errorf("state of the skipped migration = %v, want %v", status.State, Pending)
+		}
+	}
+
+	if _, newVersion, err := m.Up(ctx, -1); err != nil || newVersion != 4 {
+		t.Errorf("second Up() = %d, %v, want 4, nil", newVersion, err)
+	}
+
+	if got := m.SkippedVersions(); !reflect.DeepEqual(got, []int64{3}) {
+		t.Errorf("SkippedVersions() after second Up = %v, want [3]", got)
+	}
+
+	// the migrator without the option applies the skipped migration
+	if _, _, err := NewMigrator(db, migrations).Up(ctx, -1); err != nil {
+		t.Fatalf("Up() without skipped versions error = %v", err)
+	}
+
+	if !db.Migrator().HasTable("t3") {
+		t.Error("previously skipped migration is not applied")
+	}
+}
+
+func TestSkipVersionsDependency(t *testing.T) {
+	dependent := tableMigration(3)
+	dependent.DependsOn = []int64{2}
+
+	m := NewMigrator(openTestDB(t), []migration.Migration{tableMigration(2), dependent}, WithAutoInit(true),
+		WithSkipVersions(2))
+
+	_, _, err := m.Up(context.Background(), -1)
+
+	var depErr *DependencyError
+
+	if !errors.As(err, &depErr) || *depErr != (DependencyError{Version: 3, DependsOn: 2}) {
+		t.Errorf("Up() error = %v, want a DependencyError of 3 on 2", err)
+	}
+}
+
+func TestSkipVersionsDown(t *testing.T) {
+	ctx := context.Background()
+	m := NewMigrator(openTestDB(t), []migration.Migration{tableMigration(2), tableMigration(3), tableMigration(4)},
+		WithAutoInit(true), WithSkipVersions(3))
+
+	if _, _, err := m.Up(ctx, -1); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	// the rollback passes over the skipped migration to the previous applied one
+	if oldVersion, newVersion, err := m.Down(ctx); err != nil || oldVersion != 4 || newVersion != 2 {
+		t.Errorf("Down() = %d, %d, %v, want 4, 2, nil", oldVersion, newVersion, err)
+	}
+}
@@ -0,0 +1,30 @@
+//go:build go1.21
+// +build go1.21
+
+package migrator
+
+import "log/slog"
+
+// SlogLogger is a logger adapter which writes messages to a structured `log/slog` logger.
+type SlogLogger struct {
+	Logger *slog.Logger
+}
+
+// NewSlogLogger returns a new instance of SlogLogger. If the logger is nil the default logger is used.
+func NewSlogLogger(l *slog.Logger) *SlogLogger {
+	if l == nil {
+		l = slog.Default()
+	}
+
+	return &SlogLogger{Logger: l}
+}
+
+// Info writes an informational message.
+func (l *SlogLogger) Info(msg string, fields ...interface{}) {
+	l.Logger.Info(msg, fields...)
+}
+
+// Error writes an error message.
+func (l *SlogLogger) Error(msg string, err error, fields ...interface{}) {
+	l.Logger.Error(msg, append([]interface{}{"error", err}, fields...)...)
+}
@@ -0,0 +1,75 @@
+package migrator
+
+import (
+	"io/fs"
+	"sort"
+
+	"gorm.io/gorm"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// Source declares an interface of a migrations source.
+type Source interface {
+	Load() ([]migration.Migration, error)
+}
+
+// SliceSource is a Source which returns a copy of the slice.
+type SliceSource []migration.Migration
+
+// Load returns a copy of the slice.
+func (s SliceSource) Load() ([]migration.Migration, error) {
+	migrations := make([]migration.Migration, len(s))
+	copy(migrations, s)
+
+	return migrations, nil
+}
+
+// FSSource is a Source which reads SQL migrations from files matching the pattern (see `migration.LoadFromFS`).
+type FSSource struct {
+	FS      fs.FS
+	Pattern string
+}
+
+// Load reads SQL migrations from the file system.
+func (s FSSource) Load() ([]migration.Migration, error) {
+	return migration.LoadFromFS(s.FS, s.Pattern)
+}
+
+// CompositeSource is a Source which merges migrations of several sources. If some version is returned by more
+// than one source, the migration of the first source is used.
+type CompositeSource []Source
+
+// Load returns a sorted list of merged migrations.
+func (s CompositeSource) Load() ([]migration.Migration, error) {
+	migrations := []migration.Migration{}
+	versions := map[int64]bool{}
+
+	for _, src := range s {
+		loaded, err := src.Load()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, migr := range loaded {
+			if !versions[migr.Version] {
+				versions[migr.Version] = true
+				migrations = append(migrations, migr)
+			}
+		}
+	}
+
+	sort.Sort(migration.Migrations(migrations))
+
+	return migrations, nil
+}
+
+// NewMigratorFromSource returns a new instance of Migrator with migrations loaded from the source.
+func NewMigratorFromSource(db *gorm.DB, src Source, opts ...MigratorOption) (*Migrator, error) {
+	migrations, err := src.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMigrator(db, migrations, opts...), nil
+}
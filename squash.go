@@ -0,0 +1,64 @@
+package migrator
+
+import (
+	"strings"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// Squash returns a new SQL migration with the specified name and `toVersion` version which combines migrations
+// with versions in the range (fromVersion, toVersion]. `up` statements are concatenated in the ascending order
+// and `down` statements in the descending one. It returns `ErrorCannotSquashFuncMigrations` wrapped into
+// `VersionError` if some migration of the range is not an SQL migration. The migrator and the database
+// are not modified.
+func (m *Migrator) Squash(fromVersion, toVersion int64, name string) (squashed migration.Migration, err error) {
+	if fromVersion >= toVersion {
+		err = ErrorInvalidVersionRange
+		return
+	}
+
+	ups := []string{}
+	downs := []string{}
+
+	for _, migr := range migration.Migrations(m.migrations).Filter(fromVersion+1, toVersion) {
+		// the initial migration does nothing
+		if migr.Version == 1 {
+			continue
+		}
+
+		if migr.Up != nil || migr.UpCtx != nil || migr.Down != nil || migr.DownCtx != nil {
+			err = &VersionError{Version: migr.Version, Err: ErrorCannotSquashFuncMigrations}
+			return
+		}
+
+		ups = append(ups, trimStatement(migr.UpSQL))
+		downs = append([]string{trimStatement(migr.DownSQL)}, downs...)
+	}
+
+	if len(ups) == 0 {
+		err = ErrorNoMigrations
+		return
+	}
+
+	squashed = migration.NewSQLMigration(toVersion, name, joinStatements(ups), joinStatements(downs))
+
+	return
+}
+
+// trimStatement removes surrounding whitespaces and trailing semicolons of the SQL statement.
+func trimStatement(statement string) string {
+	return strings.TrimRight(strings.TrimSpace(statement), "; \t\r\n")
+}
+
+// joinStatements joins non-empty SQL statements with semicolons.
+func joinStatements(statements []string) string {
+	joined := make([]string, 0, len(statements))
+
+	for _, statement := range statements {
+		if statement != "" {
+			joined = append(joined, statement+";")
+		}
+	}
+
+	return strings.Join(joined, "\n")
+}
@@ -0,0 +1,88 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+func TestSquash(t *testing.T) {
+	migrations := []migration.Migration{
+		migration.NewSQLMigration(2, "create_users", "CREATE TABLE users (name TEXT);\n", "DROP TABLE users;"),
+		migration.NewSQLMigration(3, "create_orders", "  CREATE TABLE orders (id INTEGER) ", "DROP TABLE orders"),
+		migration.NewSQLMigration(4, "seed", "INSERT INTO users (name) VALUES ('admin');", ""),
+		migration.NewSQLMigration(5, "create_items", "CREATE TABLE items (id INTEGER)", "DROP TABLE items"),
+	}
+
+	m := NewMigrator(openTestDB(t), migrations)
+
+	squashed, err := m.Squash(0, 4, "initial")
+	if err != nil {
+		t.Fatalf("Squash() error = %v", err)
+	}
+
+	wantUp := "CREATE TABLE users (name TEXT);\nCREATE TABLE orders (id INTEGER);\nINSERT INTO users (name) VALUES ('admin');"
+	wantDown := "DROP TABLE orders;\nDROP TABLE users;"
+
+	if squashed.Version != 4 || squashed.Name != "initial" || squashed.UpSQL != wantUp || squashed.DownSQL != wantDown {
+		t.Fatalf("Squash() = %d %q %q %q", squashed.Version, squashed.Name, squashed.UpSQL, squashed.DownSQL)
+	}
+
+	// the squashed migration replaces the original ones
+	ctx := context.Background()
+	db := openTestDB(t)
+	m = NewMigrator(db, []migration.Migration{squashed, migrations[3]}, WithAutoInit(true))
+
+	if _, newVersion, err := m.Up(ctx, -1); err != nil || newVersion != 5 {
+		t.Fatalf("Up() = %d, %v, want 5, nil", newVersion, err)
+	}
+
+	if got := countRecords(t, db, "users"); got != 1 {
+		t.Errorf("users count = %d, want 1", got)
+	}
+
+	if _, _, err := m.DownTo(ctx, 1); err != nil {
+		t.Fatalf("DownTo() error = %v", err)
+	}
+
+	if db.Migrator().HasTable("users") || db.Migrator().HasTable("orders") {
+		t.Error("squashed migration is not rolled back")
+	}
+}
+
+func TestSquashErrors(t *testing.T) {
+	m := NewMigrator(openTestDB(t), []migration.Migration{
+		migration.NewSQLMigration(2, "create_users", "CREATE TABLE users (name TEXT)", "DROP TABLE users"),
+		tableMigration(3),
+	})
+
+	tests := []struct {
+		name    string
+		from    int64
+		to      int64
+		wantErr error
+	}{
+		{"empty range", 2, 2, ErrorInvalidVersionRange},
+		{"reversed range", 3, 2, ErrorInvalidVersionRange},
+		{"only the initial migration", 0, 1, ErrorNoMigrations},
+		{"no migrations", 3, 10, ErrorNoMigrations},
+		{"function migration", 1, 3, ErrorCannotSquashFuncMigrations},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := m.Squash(tt.from, tt.to, "squashed")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Squash() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var versionErr *VersionError
+
+			if tt.wantErr == ErrorCannotSquashFuncMigrations && (!errors.As(err, &versionErr) || versionErr.Version != 3) {
+				t.Errorf("Squash() error = %v, want a VersionError of 3", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package migrator
+
+import (
+	"context"
+	"time"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// StatusState declares a state of a migration.
+type StatusState int
+
+const (
+	// Pending means that the migration is registered but not applied.
+	Pending StatusState = iota
+	// Applied means that the migration is registered and applied.
+	Applied
+	// Missing means that the migration is applied but not registered.
+	Missing
+)
+
+func (s StatusState) String() string {
+	switch s {
+	case Pending:
+		return "pending"
+	case Applied:
+		return "applied"
+	case Missing:
+		return "missing"
+	default:
+		return "unknown"
+	}
+}
+
+// MigrationStatus declares a state of a single migration. `SemanticVersion` is set only if semantic versioning
+// is enabled.
+type MigrationStatus struct {
+	Version             int64
+	SemanticVersion     string
+	Name                string
+	State               StatusState
+	AppliedAt           time.Time
+	ExecutionDurationMs int64
+	AppliedBy           string
+	AppliedHost         string
+}
+
+// Status returns a sorted list of states of all applied and registered migrations.
+func (m *Migrator) Status(ctx context.Context) (statuses []MigrationStatus, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.status(ctx)
+}
+
+// status is like `Status` but does not hold the migrator lock.
+func (m *Migrator) status(ctx context.Context) (statuses []MigrationStatus, err error) {
+	if err = m.ensureInit(ctx); err != nil {
+		return
+	}
+
+	var history []migration.Migration
+
+	if history, err = m.loadHistory(m.session(ctx)); err != nil {
+		return
+	}
+
+	historyLength := len(history)
+	actualLength := len(m.migrations)
+	statuses = make([]MigrationStatus, 0, historyLength+actualLength)
+	i := 0
+	j := 0
+
+	for i < historyLength || j < actualLength {
+		switch {
+		case j == actualLength || (i < historyLength && history[i].Less(&m.migrations[j])):
+			statuses = append(statuses, MigrationStatus{
+				Version:             history[i].Version,
+				Name:                history[i].Name,
+				State:               Missing,
+				AppliedAt:           history[i].AppliedAt,
+				ExecutionDurationMs: history[i].ExecutionDurationMs,
+				AppliedBy:           history[i].AppliedBy,
+				AppliedHost:         history[i].AppliedHost,
+			})
+			i++
+		case i == historyLength || m.migrations[j].Less(&history[i]):
+			statuses = append(statuses, MigrationStatus{Version: m.migrations[j].Version, Name: m.migrations[j].Name, State: Pending})
+			j++
+		default:
+			statuses = append(statuses, MigrationStatus{
+				Version:             m.migrations[j].Version,
+				Name:                m.migrations[j].Name,
+				State:               Applied,
+				AppliedAt:           history[i].AppliedAt,
+				ExecutionDurationMs: history[i].ExecutionDurationMs,
+				AppliedBy:           history[i].AppliedBy,
+				AppliedHost:         history[i].AppliedHost,
+			})
+			i++
+			j++
+		}
+	}
+
+	if m.config.SemanticVersioning {
+		j = 0
+
+		for k := range statuses {
+			// registered migrations may declare semantic versions explicitly
+			for j < actualLength && m.migrations[j].Version < statuses[k].Version {
+				j++
+			}
+
+			if j < actualLength && m.migrations[j].Version == statuses[k].Version {
+				statuses[k].SemanticVersion = m.migrations[j].Semantic()
+			} else {
+				statuses[k].SemanticVersion = migration.FormatSemanticVersion(statuses[k].Version)
+			}
+		}
+	}
+
+	return
+}
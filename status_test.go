@@ -0,0 +1,55 @@
+package migrator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+func TestStatusExecutionDuration(t *testing.T) {
+	const sleep = 50 * time.Millisecond
+
+	ctx := context.Background()
+	slow := migration.Migration{
+		Version: 3,
+		Name:    "slow",
+		Up: func(db *gorm.DB) error {
+			time.Sleep(sleep)
+			return nil
+		},
+		Down: migration.DummyUpDown,
+	}
+	m := NewMigrator(openTestDB(t), []migration.Migration{tableMigration(2), slow}, WithAutoInit(true))
+
+	if _, _, err := m.Up(ctx, -1); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+
+	if len(statuses) != 3 {
+		t.Fatalf("Status() returned %d migrations, want 3", len(statuses))
+	}
+
+	for _, status := range statuses {
+		if status.State != Applied {
+			t.Errorf("migration %d state = %s, want %s", status.Version, status.State, Applied)
+		}
+
+		if status.ExecutionDurationMs < 0 {
+			t.Errorf("migration %d duration = %dms, want non-negative", status.Version, status.ExecutionDurationMs)
+		}
+	}
+
+	// the tolerance is generous to keep the test stable on loaded machines
+	if got, min, max := statuses[2].ExecutionDurationMs, sleep.Milliseconds(), (sleep + time.Second).Milliseconds(); got < min || got > max {
+		t.Errorf("slow migration duration = %dms, want within [%d, %d]ms", got, min, max)
+	}
+}
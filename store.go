@@ -0,0 +1,269 @@
+package migrator
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// MigrationStore declares an interface of a storage of the migrations history.
+type MigrationStore interface {
+	// CreateTable creates the history storage or upgrades its schema if it already exists.
+	CreateTable() error
+	InsertMigration(m migration.Migration) error
+	// DeleteMigration excludes the rolled back migration from applied ones, the store may keep its records
+	// along with the record of the rollback.
+	DeleteMigration(version int64) error
+	// ListMigrations returns applied migrations sorted by version.
+	ListMigrations() ([]migration.Migration, error)
+	// LastMigration returns the applied migration with the highest version.
+	LastMigration() (migration.Migration, error)
+	DeleteAll() error
+}
+
+// migrationCounter declares an optional interface of stores which can count history records without loading them.
+type migrationCounter interface {
+	CountMigrations() (int64, error)
+}
+
+// versionCounter declares an optional interface of stores which can count history records of the specified
+// versions without loading them.
+type versionCounter interface {
+	CountMigrationsOf(versions []int64) (int64, error)
+}
+
+// historyPager declares an optional interface of stores which can load pages of history records.
+type historyPager interface {
+	migrationCounter
+	ListMigrationsPage(offset, limit int) ([]migration.Migration, error)
+}
+
+// tableChecker declares an optional interface of stores which can check whether the history storage exists.
+type tableChecker interface {
+	HasTable() bool
+}
+
+// historyCompactor declares an optional interface of stores which keep records of rolled back migrations.
+type historyCompactor interface {
+	CompactHistory() error
+}
+
+// GORMStore is a MigrationStore implementation which keeps the history in a database table using GORM.
+// History records are never updated: every applied and rolled back migration adds a record with the `up`
+// or `down` direction, so the table is an audit log of all runs. A version is applied if its latest record
+// has the `up` direction. The log is trimmed by `CompactHistory`, `DeleteAll` (used by `SetVersion`)
+// deletes all records.
+type GORMStore struct {
+	db              *gorm.DB
+	tableName       string
+	skipAutoMigrate bool
+}
+
+var _ MigrationStore = (*GORMStore)(nil)
+
+// NewGORMStore returns a new instance of GORMStore. The table name may be qualified with a schema.
+func NewGORMStore(db *gorm.DB, tableName string) *GORMStore {
+	return &GORMStore{db: db, tableName: tableName}
+}
+
+// CreateTable creates the history table if it does not exist and upgrades its schema otherwise
+// (unless automatic migration of the schema is disabled). A table with the version as the primary key
+// (created by previous releases) is recreated with its records.
+func (s *GORMStore) CreateTable() error {
+	var mig migration.Migration
+
+	if s.HasTable() {
+		if s.skipAutoMigrate {
+			return nil
+		}
+
+		if !s.table().Migrator().HasColumn(&mig, "ID") {
+			return s.recreateTable()
+		}
+
+		return s.table().Migrator().AutoMigrate(&mig)
+	}
+
+	return s.table().Migrator().CreateTable(&mig)
+}
+
+// recreateTable recreates the history table with the actual schema and copies its records inside a transaction.
+func (s *GORMStore) recreateTable() error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		store := s.withDB(tx)
+
+		var records []migration.Migration
+
+		if err := store.table().Order("version ASC").Find(&records).Error; err != nil {
+			return err
+		}
+
+		if err := store.table().Migrator().DropTable(s.tableName); err != nil {
+			return err
+		}
+
+		if err := store.table().Migrator().CreateTable(&migration.Migration{}); err != nil {
+			return err
+		}
+
+		if len(records) == 0 {
+			return nil
+		}
+
+		// records are copied as they are, so audit hooks are not called
+		return store.table().Session(&gorm.Session{SkipHooks: true}).Create(&records).Error
+	})
+}
+
+// HasTable returns `true` if the history table exists.
+func (s *GORMStore) HasTable() bool {
+	return s.db.Migrator().HasTable(s.tableName)
+}
+
+// InsertMigration inserts a history record of the applied migration. It returns `ErrorAlreadyApplied` wrapped
+// into `VersionError` if the migration is already applied (e.g. if two processes apply the same migration
+// without the migrator lock).
+func (s *GORMStore) InsertMigration(m migration.Migration) error {
+	m.ID = 0
+	m.AppliedDirection = DirectionUp
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		store := s.withDB(tx)
+
+		var count int64
+
+		if err := store.applied().Where("version = ?", m.Version).Count(&count).Error; err != nil {
+			return err
+		}
+
+		if count > 0 {
+			return &VersionError{Version: m.Version, Err: ErrorAlreadyApplied}
+		}
+
+		return store.table().Create(&m).Error
+	})
+}
+
+// DeleteMigration inserts a history record of the rolled back migration with the name and the checksum
+// of the applied one. Audit fields are set by audit functions only (see `migration.RegisterAuditFunc`).
+// It does nothing if the version is not applied.
+func (s *GORMStore) DeleteMigration(version int64) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		store := s.withDB(tx)
+
+		var applied []migration.Migration
+
+		if err := store.applied().Where("version = ?", version).Limit(1).Find(&applied).Error; err != nil {
+			return err
+		}
+
+		if len(applied) == 0 {
+			return nil
+		}
+
+		m := migration.Migration{
+			Version:          version,
+			Name:             applied[0].Name,
+			Checksum:         applied[0].Checksum,
+			AppliedAt:        time.Now().UTC(),
+			AppliedDirection: DirectionDown,
+		}
+
+		return store.table().Create(&m).Error
+	})
+}
+
+// ListMigrations returns history records of applied migrations sorted by version.
+func (s *GORMStore) ListMigrations() (migrations []migration.Migration, err error) {
+	migrations = []migration.Migration{}
+
+	if result := s.applied().Order("version ASC").Find(&migrations); result.Error != nil {
+		err = result.Error
+	}
+
+	return
+}
+
+// ListMigrationsPage returns at most `limit` history records of applied migrations sorted by version
+// skipping `offset` records.
+func (s *GORMStore) ListMigrationsPage(offset, limit int) (migrations []migration.Migration, err error) {
+	migrations = []migration.Migration{}
+
+	if result := s.applied().Order("version ASC").Offset(offset).Limit(limit).Find(&migrations); result.Error != nil {
+		err = result.Error
+	}
+
+	return
+}
+
+// LastMigration returns the history record of the applied migration with the highest version.
+func (s *GORMStore) LastMigration() (mig migration.Migration, err error) {
+	if result := s.applied().Order("version DESC").First(&mig); result.Error != nil {
+		err = result.Error
+	}
+
+	return
+}
+
+// CountMigrations returns the number of history records of applied migrations.
+func (s *GORMStore) CountMigrations() (count int64, err error) {
+	err = s.applied().Count(&count).Error
+
+	return
+}
+
+// CountMigrationsOf returns the number of applied migrations of the specified versions.
+func (s *GORMStore) CountMigrationsOf(versions []int64) (count int64, err error) {
+	if len(versions) == 0 {
+		return
+	}
+
+	err = s.applied().Where("version IN ?", versions).Count(&count).Error
+
+	return
+}
+
+// CompactHistory deletes history records of rolled back migrations and previous runs of applied ones,
+// so only the latest record of every applied version is kept.
+func (s *GORMStore) CompactHistory() error {
+	var ids []int64
+
+	// identifiers are loaded first, because MySQL does not delete rows selected from the same table
+	if err := s.applied().Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return s.DeleteAll()
+	}
+
+	return s.table().Delete(&migration.Migration{}, "id NOT IN ?", ids).Error
+}
+
+// DeleteAll deletes all history records.
+func (s *GORMStore) DeleteAll() error {
+	return s.table().Delete(&migration.Migration{}, "1 = 1").Error
+}
+
+// withDB returns a copy of the store which uses the specified DB instance.
+func (s *GORMStore) withDB(db *gorm.DB) *GORMStore {
+	return &GORMStore{db: db, tableName: s.tableName, skipAutoMigrate: s.skipAutoMigrate}
+}
+
+// applied returns a DB instance bound to the latest history records of applied versions. Records without
+// the direction were inserted before directions were stored and are considered applied.
+func (s *GORMStore) applied() *gorm.DB {
+	latest := s.db.Table(s.tableName).Select("MAX(id)").Group("version")
+
+	return s.table().Where("id IN (?)", latest).
+		Where("applied_direction IS NULL OR applied_direction <> ?", DirectionDown)
+}
+
+// table returns a DB instance bound to the history table.
+func (s *GORMStore) table() *gorm.DB {
+	// GORM ignores the schema qualifier of the table on insert, so the clause is specified explicitly
+	return s.db.Table(s.tableName).Clauses(clause.Insert{Table: clause.Table{Name: s.tableName}})
+}
@@ -0,0 +1,159 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// historyEntry declares a version and a direction of a history record.
+type historyEntry struct {
+	version   int64
+	direction string
+}
+
+// historyEntries returns all records of the history table in the order of insertion.
+func historyEntries(t *testing.T, s *GORMStore) []historyEntry {
+	t.Helper()
+
+	var records []migration.Migration
+
+	if err := s.table().Order("id ASC").Find(&records).Error; err != nil {
+		t.Fatalf("find records: %v", err)
+	}
+
+	entries := make([]historyEntry, len(records))
+
+	for i, record := range records {
+		entries[i] = historyEntry{record.Version, record.AppliedDirection}
+	}
+
+	return entries
+}
+
+func TestGORMStoreKeepsAllRuns(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	m := NewMigrator(db, []migration.Migration{tableMigration(2)}, WithAutoInit(true))
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := m.Up(ctx, -1); err != nil {
+			t.Fatalf("Up() error = %v", err)
+		}
+
+		if _, _, err := m.Down(ctx); err != nil {
+			t.Fatalf("Down() error = %v", err)
+		}
+	}
+
+	if _, _, err := m.Up(ctx, -1); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	store := NewGORMStore(db, DefaultMigrationTable)
+	want := []historyEntry{
+		{1, DirectionUp},
+		{2, DirectionUp}, {2, DirectionDown},
+		{2, DirectionUp}, {2, DirectionDown},
+		{2, DirectionUp},
+	}
+
+	if got := historyEntries(t, store); !reflect.DeepEqual(got, want) {
+		t.Errorf("records = %v, want %v", got, want)
+	}
+
+	if _, version, err := m.Version(ctx); err != nil || version != 2 {
+		t.Errorf("Version() = %d, %v, want 2, nil", version, err)
+	}
+
+	history, err := m.History(ctx)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+
+	if got := versionsOf(history); !reflect.DeepEqual(got, []int64{1, 2}) {
+		t.Errorf("History() versions = %v, want [1 2]", got)
+	}
+
+	if err := m.Compact(ctx); err != nil {
+		t.Fatalf("Compact() error = %v", err)
+	}
+
+	if got := historyEntries(t, store); !reflect.DeepEqual(got, []historyEntry{{1, DirectionUp}, {2, DirectionUp}}) {
+		t.Errorf("records after Compact() = %v, want the latest records of applied versions", got)
+	}
+}
+
+func TestGORMStoreInsertAppliedMigration(t *testing.T) {
+	store := NewGORMStore(openTestDB(t), DefaultMigrationTable)
+
+	if err := store.CreateTable(); err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+
+	if err := store.InsertMigration(migration.Migration{Version: 2, Name: "create_t2"}); err != nil {
+		t.Fatalf("InsertMigration() error = %v", err)
+	}
+
+	err := store.InsertMigration(migration.Migration{Version: 2, Name: "create_t2"})
+
+	var versionErr *VersionError
+
+	if !errors.Is(err, ErrorAlreadyApplied) || !errors.As(err, &versionErr) || versionErr.Version != 2 {
+		t.Errorf("InsertMigration() error = %v, want %v of the version 2", err, ErrorAlreadyApplied)
+	}
+
+	// rolling back a version which is not applied adds nothing
+	if err := store.DeleteMigration(3); err != nil {
+		t.Fatalf("DeleteMigration() error = %v", err)
+	}
+
+	if got := historyEntries(t, store); !reflect.DeepEqual(got, []historyEntry{{2, DirectionUp}}) {
+		t.Errorf("records = %v, want a single applied record", got)
+	}
+}
+
+func TestGORMStoreUpgradesLegacyTable(t *testing.T) {
+	db := openTestDB(t)
+
+	// the schema of previous releases uses the version as the primary key
+	if err := db.Exec("CREATE TABLE " + DefaultMigrationTable + " (version integer PRIMARY KEY, name text," +
+		" applied_at datetime, execution_duration_ms integer, checksum text, applied_direction text," +
+		" applied_by text, applied_host text)").Error; err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	if err := db.Exec("INSERT INTO " + DefaultMigrationTable + " (version, name, applied_direction) VALUES" +
+		" (1, '-', 'up'), (2, 'create_t2', 'down'), (3, 'create_t3', NULL)").Error; err != nil {
+		t.Fatalf("insert records: %v", err)
+	}
+
+	store := NewGORMStore(db, DefaultMigrationTable)
+
+	if err := store.CreateTable(); err != nil {
+		t.Fatalf("CreateTable() error = %v", err)
+	}
+
+	want := []historyEntry{{1, DirectionUp}, {2, DirectionDown}, {3, ""}}
+
+	if got := historyEntries(t, store); !reflect.DeepEqual(got, want) {
+		t.Errorf("records = %v, want %v", got, want)
+	}
+
+	history, err := store.ListMigrations()
+	if err != nil {
+		t.Fatalf("ListMigrations() error = %v", err)
+	}
+
+	if got := versionsOf(history); !reflect.DeepEqual(got, []int64{1, 3}) {
+		t.Errorf("ListMigrations() versions = %v, want [1 3]", got)
+	}
+
+	// the version 2 can be applied again
+	if err := store.InsertMigration(migration.Migration{Version: 2, Name: "create_t2"}); err != nil {
+		t.Errorf("InsertMigration() error = %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package migrator
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// RunByTags applies pending migrations (`up` direction) or rolls back the current migration (`down` direction)
+// considering only migrations which have at least one of the specified tags. All migrations are considered
+// if the list of tags is empty.
+func (m *Migrator) RunByTags(ctx context.Context, tags []string, direction string) (res Result, err error) {
+	switch direction {
+	case DirectionUp:
+		return m.withTags(tags).UpResult(ctx, -1)
+	case DirectionDown:
+		return m.withTags(tags).DownResult(ctx)
+	default:
+		err = ErrorInvalidDirection
+		return
+	}
+}
+
+// withTags returns a copy of the migrator which considers only migrations with the specified tags.
+func (m *Migrator) withTags(tags []string) *Migrator {
+	return &Migrator{db: m.db, store: m.store, migrations: filterByTags(m.migrations, tags), config: m.config,
+		autoInit: m.autoInit, skipped: m.skipped, mu: m.mu}
+}
+
+// filterByTags returns migrations which have at least one of the specified tags. The initial migration is always
+// kept. All migrations are returned if the list of tags is empty.
+func filterByTags(migrations []migration.Migration, tags []string) []migration.Migration {
+	if len(tags) == 0 {
+		return migrations
+	}
+
+	filtered := make([]migration.Migration, 0, len(migrations))
+
+	for _, migr := range migrations {
+		if migr.Version == 1 || migr.HasAnyTag(tags) {
+			filtered = append(filtered, migr)
+		}
+	}
+
+	return filtered
+}
+
+// splitTags splits the comma-separated value of the `--tags` option.
+func splitTags(value string) []string {
+	tags := []string{}
+
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
@@ -0,0 +1,81 @@
+package migrator
+
+import "github.com/Devoter/gorm-migrator/migration"
+
+// Validate checks registered migrations. It returns `ErrorInvalidVersion` if some version is not positive,
+// `ErrorDuplicateVersion` if some version is registered more than once, `ErrorNilApplyFunc` if some migration
+// has neither `up` or `down` function nor SQL statement and the error of the version validator if it is
+// configured. If the maximum name length is configured, longer names are reported as `ErrorNameTooLong`.
+// All errors are wrapped into `VersionError` except `migration.CyclicDependencyError` of circular dependencies.
+// If the maximum version gap is configured, larger gaps are reported to the logger. Irreversible migrations
+// are reported to the callback of `WithWarnOnMissingDown` if it is set. Warnings are reported for all migrations
+// before they are checked, so an invalid migration does not hide warnings of the following ones.
+func (m *Migrator) Validate() error {
+	m.reportWarnings()
+
+	if err := m.validateVersions(); err != nil {
+		return err
+	}
+
+	if err := migration.DetectCycles(m.migrations); err != nil {
+		return err
+	}
+
+	for i, migr := range m.migrations {
+		if migr.Version <= 0 {
+			return &VersionError{Version: migr.Version, Err: ErrorInvalidVersion}
+		}
+
+		if m.config.MaxNameLength > 0 && len(migr.Name) > m.config.MaxNameLength {
+			return &VersionError{Version: migr.Version, Err: ErrorNameTooLong}
+		}
+
+		if i > 0 && m.migrations[i-1].Eq(&migr) {
+			return &VersionError{Version: migr.Version, Err: ErrorDuplicateVersion}
+		}
+
+		if !migr.HasUp() || !migr.HasDown() {
+			return &VersionError{Version: migr.Version, Err: ErrorNilApplyFunc}
+		}
+	}
+
+	return nil
+}
+
+// reportWarnings reports large gaps between versions to the logger and irreversible migrations to the callback
+// of `WithWarnOnMissingDown`.
+func (m *Migrator) reportWarnings() {
+	for i, migr := range m.migrations {
+		if i > 0 {
+			prev := m.migrations[i-1]
+
+			if m.config.MaxVersionGap > 0 && migr.Version-prev.Version > m.config.MaxVersionGap {
+				m.config.Logger.Info("Large gap between migration versions", "from", prev.Version, "to", migr.Version)
+			}
+		}
+
+		if m.config.WarnOnMissingDown != nil && migr.Version > 1 && (!migr.HasDown() || migration.IsDummyUpDown(migr.Down)) {
+			m.config.WarnOnMissingDown(migr.Version, migr.Name)
+		}
+	}
+}
+
+// validateVersions calls the version validator for registered migrations except the initial one
+// in the ascending order.
+func (m *Migrator) validateVersions() error {
+	if m.config.VersionValidator == nil {
+		return nil
+	}
+
+	for _, migr := range m.migrations {
+		if migr.Version == 1 {
+			continue
+		}
+
+		if err := m.config.VersionValidator(migr.Version); err != nil {
+			return &VersionError{Version: migr.Version, Err: err}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package migrator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+func TestValidateVersions(t *testing.T) {
+	tests := []struct {
+		name        string
+		versions    []int64
+		wantVersion int64
+		wantErr     error
+	}{
+		{"only the initial migration", nil, 0, nil},
+		{"positive versions", []int64{2, 3}, 0, nil},
+		{"zero version", []int64{0, 2}, 0, ErrorInvalidVersion},
+		{"negative version", []int64{-5, 2}, -5, ErrorInvalidVersion},
+		{"duplicate of the initial migration", []int64{1}, 1, ErrorDuplicateVersion},
+		{"duplicate version", []int64{2, 2}, 2, ErrorDuplicateVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations := make([]migration.Migration, len(tt.versions))
+
+			for i, version := range tt.versions {
+				migrations[i] = tableMigration(version)
+			}
+
+			err := NewMigrator(nil, migrations).Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err == nil {
+				return
+			}
+
+			var versionErr *VersionError
+
+			if !errors.As(err, &versionErr) || versionErr.Version != tt.wantVersion {
+				t.Errorf("Validate() error = %v, want an error of the version %d", err, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestValidateWarnsAfterInvalidMigration(t *testing.T) {
+	irreversible := tableMigration(4)
+	irreversible.Down = nil
+
+	var warned []int64
+
+	m := NewMigrator(nil, []migration.Migration{{Version: 2, Name: "empty"}, tableMigration(3), irreversible},
+		WithWarnOnMissingDown(func(version int64, name string) { warned = append(warned, version) }))
+
+	if err := m.Validate(); !errors.Is(err, ErrorNilApplyFunc) {
+		t.Fatalf("Validate() error = %v, want %v", err, ErrorNilApplyFunc)
+	}
+
+	if !reflect.DeepEqual(warned, []int64{2, 4}) {
+		t.Errorf("warned versions = %v, want [2 4]", warned)
+	}
+}
@@ -0,0 +1,52 @@
+package migrator
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+// VersionValidator declares a func type which checks a migration version. It is called for registered
+// migrations except the initial one.
+type VersionValidator func(version int64) error
+
+// TimestampVersionValidator checks that the version is a 14-digit `YYYYMMDDHHmmSS` timestamp.
+func TimestampVersionValidator(version int64) error {
+	if _, err := time.Parse(migration.TimestampVersionLayout, strconv.FormatInt(version, 10)); err != nil {
+		return ErrorVersionValidationFailed
+	}
+
+	return nil
+}
+
+// SequentialVersionValidator returns a validator which checks that versions of the migrations form a contiguous
+// sequence which continues the initial version 1. The validator keeps no state, every version is checked against
+// the previous one in the sorted list of the migrations, so the list must contain all registered migrations.
+func SequentialVersionValidator(migrations []migration.Migration) VersionValidator {
+	versions := []int64{1}
+
+	for _, migr := range migrations {
+		versions = append(versions, migr.Version)
+	}
+
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+
+	// the previous distinct version of every version
+	prev := make(map[int64]int64, len(versions))
+
+	for i := 1; i < len(versions); i++ {
+		if versions[i] != versions[i-1] {
+			prev[versions[i]] = versions[i-1]
+		}
+	}
+
+	return func(version int64) error {
+		if p, ok := prev[version]; !ok || version != p+1 {
+			return ErrorVersionValidationFailed
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,71 @@
+package migrator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Devoter/gorm-migrator/migration"
+)
+
+func TestTimestampVersionValidator(t *testing.T) {
+	tests := []struct {
+		version int64
+		valid   bool
+	}{
+		{20240101120000, true},
+		{20241231235959, true},
+		{20241301120000, false},
+		{2024010112000, false},
+		{2, false},
+	}
+
+	for _, tt := range tests {
+		if err := TimestampVersionValidator(tt.version); (err == nil) != tt.valid {
+			t.Errorf("TimestampVersionValidator(%d) error = %v, want valid = %t", tt.version, err, tt.valid)
+		}
+	}
+}
+
+func TestSequentialVersionValidator(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []int64
+		invalid  int64
+	}{
+		{"contiguous", []int64{2, 3, 4}, 0},
+		{"unsorted", []int64{4, 2, 3}, 0},
+		{"gap", []int64{2, 4}, 4},
+		{"not continuing the initial version", []int64{3, 4}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations := make([]migration.Migration, len(tt.versions))
+
+			for i, version := range tt.versions {
+				migrations[i] = tableMigration(version)
+			}
+
+			validator := SequentialVersionValidator(migrations)
+
+			// the validator keeps no state, so repeated validation gives the same result
+			for i := 0; i < 2; i++ {
+				err := NewMigrator(nil, migrations, WithVersionValidator(validator)).Validate()
+
+				var versionErr *VersionError
+
+				switch {
+				case tt.invalid == 0 && err != nil:
+					t.Errorf("Validate() error = %v, want nil", err)
+				case tt.invalid != 0 && (!errors.Is(err, ErrorVersionValidationFailed) || !errors.As(err, &versionErr) ||
+					versionErr.Version != tt.invalid):
+					t.Errorf("Validate() error = %v, want %v of the version %d", err, ErrorVersionValidationFailed, tt.invalid)
+				}
+			}
+
+			if err := validator(9); err == nil {
+				t.Error("validator() of an unknown version error = nil, want an error")
+			}
+		})
+	}
+}
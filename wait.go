@@ -0,0 +1,45 @@
+package migrator
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WaitForDatabase pings the database with the interval until it responds or the context is done.
+// It returns the context error in the latter case.
+func WaitForDatabase(ctx context.Context, db *gorm.DB, interval time.Duration) error {
+	return waitForDatabase(ctx, db, interval, NoopLogger{})
+}
+
+// waitForDatabase is like `WaitForDatabase` but logs failed attempts.
+func waitForDatabase(ctx context.Context, db *gorm.DB, interval time.Duration, logger Logger) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		err := db.WithContext(ctx).Exec("SELECT 1").Error
+		if err == nil {
+			return nil
+		}
+
+		logger.Info("Database is not available, retrying", "attempt", attempt, "interval", interval, "error", err)
+		timer.Reset(interval)
+	}
+}
+
+// waitForDB waits for the database if it is enabled by `WithWaitForDB`.
+func (m *Migrator) waitForDB(ctx context.Context) error {
+	if m.config.WaitForDBInterval <= 0 || m.db == nil {
+		return nil
+	}
+
+	return waitForDatabase(ctx, m.db, m.config.WaitForDBInterval, m.config.Logger)
+}